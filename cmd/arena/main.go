@@ -0,0 +1,145 @@
+// Command arena plays two bots against each other and reports which one is stronger
+//
+//	go run ./cmd/arena -a mcts:sims=800 -b mcts:sims=800,c=1.0 -games 200 -size 9 -sprt
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/awesohame/gogo/internal/arena"
+	"github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/internal/game"
+)
+
+func main() {
+	specA := flag.String("a", "mcts:sims=400", "bot A, see below")
+	specB := flag.String("b", "greedy", "bot B, see below")
+	games := flag.Int("games", 100, "no. of games, colours alternate with A black first")
+	size := flag.String("size", "9", "board size, like 9 or 13x9")
+	komi := flag.Float64("komi", 7.5, "komi")
+	tromp := flag.Bool("tromp-taylor", false, "score every stone on the board as alive")
+	moveTime := flag.Float64("time", 0, "time limit per move in sec for mcts, puct and alphabeta bots that set none")
+	maxMoves := flag.Int("max-moves", 0, "moves before a game is scored as it stands (if 0, 3 per point)")
+	concurrency := flag.Int("concurrency", runtime.NumCPU(), "games played at once")
+	out := flag.String("out", "", "directory to save every game to as SGF")
+	elo0 := flag.Float64("elo0", 0, "SPRT null hypothesis, Elo of A over B")
+	elo1 := flag.Float64("elo1", 10, "SPRT alternative hypothesis, Elo of A over B")
+	alpha := flag.Float64("alpha", 0.05, "SPRT false positive rate")
+	beta := flag.Float64("beta", 0.05, "SPRT false negative rate")
+	stopEarly := flag.Bool("sprt", false, "stop as soon as the SPRT reaches a verdict")
+	quiet := flag.Bool("q", false, "only print the final report")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: arena [flags]\n\nflags:\n")
+		flag.PrintDefaults()
+		fmt.Fprint(flag.CommandLine.Output(), `
+bots are written kind:key=value,... with the kinds
+  mcts       sims (800), time, c (1.414), reuse (true)
+  alphabeta  depth (3), nodes (200000), time, eval (simple, influence or hybrid)
+  puct       net (weight file), sims (800), time, cpuct (1.5), blend (share of the net value, 1)
+  heuristic  eval (hybrid)
+  greedy
+  random     seed (1)
+every kind also takes name=... to label it in the report and records
+`)
+	}
+	flag.Parse()
+
+	a, err := arena.ParsePlayer(*specA, *moveTime, *komi)
+	if err != nil {
+		fail(err)
+	}
+	b, err := arena.ParsePlayer(*specB, *moveTime, *komi)
+	if err != nil {
+		fail(err)
+	}
+	width, height, err := engine.ParseBoardSize(*size)
+	if err != nil {
+		fail(err)
+	}
+	if *out != "" {
+		if err := os.MkdirAll(*out, 0o755); err != nil {
+			fail(err)
+		}
+	}
+
+	cfg := arena.Config{
+		Width:       width,
+		Height:      height,
+		Komi:        *komi,
+		Games:       *games,
+		Concurrency: *concurrency,
+		MaxMoves:    *maxMoves,
+	}
+	if *tromp {
+		cfg.Rules = game.TrompTaylorRules
+	}
+	sprt := arena.SPRT{Elo0: *elo0, Elo1: *elo1, Alpha: *alpha, Beta: *beta}
+
+	fmt.Printf("%s (A) vs %s (B), %d games on %dx%d, komi %g\n", a.Name, b.Name, *games, width, height, *komi)
+	stats, err := arena.Run(cfg, a, b, func(record arena.GameRecord, stats arena.Stats) bool {
+		if *out != "" {
+			if err := saveRecord(*out, record); err != nil {
+				fmt.Fprintf(os.Stderr, "arena: %v\n", err)
+			}
+		}
+		if !*quiet {
+			fmt.Printf("game %3d  %-20s %-20s %-8s  A %d-%d-%d  LLR %.2f\n", record.Round+1,
+				"B "+record.Black, "W "+record.White, record.Result(),
+				stats.Wins, stats.Losses, stats.Draws, sprt.LLR(stats))
+		}
+		return !*stopEarly || sprt.Verdict(stats) == arena.Continue
+	})
+	if err != nil {
+		fail(err)
+	}
+	report(stats, sprt)
+}
+
+// prints the final results of the match
+func report(stats arena.Stats, sprt arena.SPRT) {
+	lo, hi := stats.ScoreInterval()
+	elo, eloLo, eloHi := stats.Elo()
+	lower, upper := sprt.Bounds()
+
+	fmt.Println()
+	fmt.Printf("games     %d  (A won %d, lost %d, drew %d, %d forfeits)\n",
+		stats.Games, stats.Wins, stats.Losses, stats.Draws, stats.Forfeits)
+	fmt.Printf("score     %.1f%% of the points for A, 95%% CI [%.1f%%, %.1f%%]\n", 100*stats.Score(), 100*lo, 100*hi)
+	fmt.Printf("elo       %s, 95%% CI [%s, %s]\n", formatElo(elo), formatElo(eloLo), formatElo(eloHi))
+	fmt.Printf("sprt      elo0 %g elo1 %g: LLR %.2f in [%.2f, %.2f], %v\n",
+		sprt.Elo0, sprt.Elo1, sprt.LLR(stats), lower, upper, sprt.Verdict(stats))
+}
+
+// formats an Elo difference with its sign
+func formatElo(elo float64) string {
+	if math.IsInf(elo, 0) {
+		if elo > 0 {
+			return "+inf"
+		}
+		return "-inf"
+	}
+	return fmt.Sprintf("%+.1f", elo)
+}
+
+// writes a game to dir as game-NNNN.sgf
+func saveRecord(dir string, record arena.GameRecord) error {
+	f, err := os.Create(filepath.Join(dir, fmt.Sprintf("game-%04d.sgf", record.Round+1)))
+	if err != nil {
+		return err
+	}
+	if err := arena.WriteSGF(f, record); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "arena: %v\n", err)
+	os.Exit(2)
+}
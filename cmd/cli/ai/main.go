@@ -6,21 +6,22 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/awesohame/gogo/internal/ai"
 	"github.com/awesohame/gogo/internal/engine"
 )
 
 func main() {
-	fmt.Println("=== Go Game: Play against MCTS AI ===")
+	fmt.Println("=== Go Game: Play against the AI ===")
 	fmt.Println()
 
 	// game setup
-	fmt.Print("Board size (9, 13, or 19): ")
-	size := readInt(9)
+	fmt.Print("Board size (9, 13, 19 or WxH like 13x9): ")
+	width, height := readSize(9)
 
-	fmt.Print("AI strength - number of simulations (100-5000): ")
-	simulations := readInt(500)
+	fmt.Print("AI (1=MCTS, 2=alpha-beta, 3=heuristic, 4=greedy, 5=random): ")
+	bot := readBot()
 
 	fmt.Print("Play as (1=Black, 2=White): ")
 	humanColor := engine.Black
@@ -29,15 +30,14 @@ func main() {
 	}
 
 	// create game
-	board := engine.NewBoard(size)
-	bot := ai.NewMCTSBot(simulations)
+	board := engine.NewRectBoard(width, height)
 
 	currentColor := engine.Black
 	passCount := 0
 	moveNumber := 1
 
 	fmt.Println("\n=== Game Start ===")
-	fmt.Println("Commands: <x> <y> to play, 'pass' to pass, 'quit' to exit")
+	fmt.Println("Commands: D4, dd or <x> <y> to play, 'pass' to pass, 'quit' to exit")
 	fmt.Println()
 
 	// game loop
@@ -92,8 +92,7 @@ func main() {
 			continue
 		}
 
-		x, y := board.ToXY(move.Point)
-		fmt.Printf("%v plays at (%d, %d)\n", colorName(currentColor), x, y)
+		fmt.Printf("%v plays at %s\n", colorName(currentColor), board.FormatGTP(move.Point))
 
 		board = newBoard
 		currentColor = opponentColor(currentColor)
@@ -137,6 +136,44 @@ func readInt(defaultVal int) int {
 	return val
 }
 
+// asks for the kind of bot and its strength, MCTS by default
+func readBot() ai.Bot {
+	switch readInt(1) {
+	case 2:
+		fmt.Print("AI strength - search depth in plies (1-4): ")
+		bot := ai.NewAlphaBetaBot(ai.NewHybridEvaluator())
+		bot.MaxDepth = readInt(3)
+		return bot
+	case 3:
+		return ai.NewHeuristicBot()
+	case 4:
+		return ai.NewGreedyBot()
+	case 5:
+		return ai.NewRandomBot(time.Now().UnixNano())
+	default:
+		fmt.Print("AI strength - number of simulations (100-5000): ")
+		return ai.NewMCTSBot(readInt(500))
+	}
+}
+
+func readSize(defaultVal int) (int, int) {
+	reader := bufio.NewReader(os.Stdin)
+	line, _ := reader.ReadString('\n')
+	line = strings.TrimSpace(line)
+
+	if line == "" {
+		return defaultVal, defaultVal
+	}
+
+	width, height, err := engine.ParseBoardSize(line)
+	if err != nil {
+		fmt.Printf("%v, using %dx%d\n", err, defaultVal, defaultVal)
+		return defaultVal, defaultVal
+	}
+
+	return width, height
+}
+
 func readMove(board *engine.Board, color engine.Color) engine.Move {
 	reader := bufio.NewReader(os.Stdin)
 
@@ -152,26 +189,12 @@ func readMove(board *engine.Board, color engine.Color) engine.Move {
 			return engine.Move{Point: -1, Color: color}
 		}
 
-		parts := strings.Fields(line)
-		if len(parts) != 2 {
-			fmt.Print("Invalid format. Try '<x> <y>' or 'pass': ")
-			continue
-		}
-
-		x, err1 := strconv.Atoi(parts[0])
-		y, err2 := strconv.Atoi(parts[1])
-
-		if err1 != nil || err2 != nil {
-			fmt.Print("Invalid coordinates. Try again: ")
-			continue
-		}
-
-		if x < 1 || x > board.Size() || y < 1 || y > board.Size() {
-			fmt.Printf("Coordinates must be between 1 and %d. Try again: ", board.Size())
+		point, err := board.ParseCoord(line)
+		if err != nil {
+			fmt.Printf("%v. Try D4, dd, '<x> <y>' or 'pass': ", err)
 			continue
 		}
 
-		point := board.ToPoint(x, y)
 		return engine.Move{Point: point, Color: color}
 	}
 }
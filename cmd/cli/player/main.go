@@ -4,31 +4,38 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 
 	"github.com/awesohame/gogo/pkg/engine"
 )
 
 func main() {
-	fmt.Println("GoGo CLI 2-player dev tool (9x9)")
+	// optional board size argument, e.g. 13 or 19x11
+	width, height := 9, 9
+	if len(os.Args) > 1 {
+		w, h, err := engine.ParseBoardSize(os.Args[1])
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		width, height = w, h
+	}
+
+	fmt.Printf("GoGo CLI 2-player dev tool (%dx%d)\n", width, height)
 	reader := bufio.NewReader(os.Stdin)
-	game := engine.NewGame(9)
+	game := engine.NewRectGame(width, height, engine.WithKomi(6.5))
 	for {
 		fmt.Println("\nCurrent board:")
 		fmt.Print(game.CurrentBoard().String())
 		if game.IsGameOver() {
 			fmt.Println("Game over!")
-			score := game.GetScore()
-			fmt.Printf("Score - Black: %d, White: %d\n", score.Black, score.White)
+			black, white, winner := game.GetFinalScore()
+			fmt.Printf("Score - Black: %.1f, White: %.1f (komi %.1f), winner: %s\n", black, white, game.Komi(), engine.ColorName(winner))
 			break
 		}
 		turn := game.CurrentTurn()
-		turnStr := "Black"
-		if turn == 2 { // engine.White
-			turnStr = "White"
-		}
-		fmt.Printf("%s's turn. Enter move (x y), or command (pass, resign, undo, redo, exit): ", turnStr)
+		turnStr := engine.ColorName(turn)
+		fmt.Printf("%s's turn. Enter move (D4, dd or x y), or command (pass, resign, undo, redo, exit): ", turnStr)
 		input, _ := reader.ReadString('\n')
 		input = strings.TrimSpace(input)
 		switch strings.ToLower(input) {
@@ -54,22 +61,21 @@ func main() {
 			fmt.Println("Exiting game.")
 			return
 		default:
-			parts := strings.Fields(input)
-			if len(parts) == 2 {
-				x, err1 := strconv.Atoi(parts[0])
-				y, err2 := strconv.Atoi(parts[1])
-				if err1 != nil || err2 != nil || x < 1 || y < 1 || x > game.Size() || y > game.Size() {
-					fmt.Println("Invalid coordinates. Enter x y with 1 <= x,y <=", game.Size())
-					continue
-				}
-				move := game.NewMove(x, y, turn)
-				err := game.MakeMove(move)
-				if err != nil {
-					fmt.Println("Error:", err)
-				}
-			} else {
-				fmt.Println("Unknown command. Enter move as 'x y' or a valid command.")
+			move, err := game.ParseMove(input)
+			if err != nil {
+				fmt.Printf("Error: %v. Enter a move like D4, dd or '4 6' (x y from the top left), or a valid command.\n", err)
+				continue
+			}
+			result, err := game.MakeMoveWithResult(move)
+			if err != nil {
+				fmt.Println("Error:", err)
+				continue
+			}
+			fmt.Printf("%s played", game.FormatMove(move))
+			if len(result.Captured) > 0 {
+				fmt.Printf(", capturing %d", len(result.Captured))
 			}
+			fmt.Println()
 		}
 	}
 }
@@ -0,0 +1,125 @@
+// Command selfplay plays MCTSBot against itself and saves the searched positions for training
+//
+//	go run ./cmd/selfplay -games 100 -size 9 -sims 400 -out selfplay.gsp
+//
+// see internal/selfplay/format.go for the file format
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/internal/game"
+	"github.com/awesohame/gogo/internal/selfplay"
+)
+
+func main() {
+	games := flag.Int("games", 100, "no. of games")
+	size := flag.String("size", "9", "board size, like 9 or 13x9")
+	komi := flag.Float64("komi", 7.5, "komi")
+	tromp := flag.Bool("tromp-taylor", false, "score every stone on the board as alive")
+	sims := flag.Int("sims", 400, "MCTS simulations per move")
+	opening := flag.Int("opening", 8, "games start with up to this many random moves, not recorded")
+	temperature := flag.Float64("temp", 1, "temperature of the move draws, 0 plays the most visited move")
+	temperatureMoves := flag.Int("temp-moves", 20, "recorded moves drawn with the temperature")
+	maxMoves := flag.Int("max-moves", 0, "moves before a game is scored as it stands (if 0, 3 per point)")
+	concurrency := flag.Int("concurrency", runtime.NumCPU(), "games played at once")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed of the openings and move draws")
+	out := flag.String("out", "selfplay.gsp", "file to write, games are appended to an existing one")
+	flag.Parse()
+
+	width, height, err := engine.ParseBoardSize(*size)
+	if err != nil {
+		fail(err)
+	}
+	cfg := selfplay.Config{
+		Width:            width,
+		Height:           height,
+		Komi:             *komi,
+		Games:            *games,
+		Simulations:      *sims,
+		OpeningMoves:     *opening,
+		Temperature:      *temperature,
+		TemperatureMoves: *temperatureMoves,
+		MaxMoves:         *maxMoves,
+		Concurrency:      *concurrency,
+		Seed:             *seed,
+	}
+	if *tromp {
+		cfg.Rules = game.TrompTaylorRules
+	}
+
+	f, w, err := openOutput(*out)
+	if err != nil {
+		fail(err)
+	}
+
+	start := time.Now()
+	done, positions := 0, 0
+	err = selfplay.Generate(cfg, func(g selfplay.Game) error {
+		done++
+		positions += len(g.Positions)
+		fmt.Printf("game %d/%d: %d positions, %s, %.0fs\n", done, *games, len(g.Positions),
+			result(g), time.Since(start).Seconds())
+		return w.WriteGame(g)
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fail(err)
+	}
+	fmt.Printf("wrote %d positions from %d games to %s\n", positions, done, *out)
+}
+
+// opens the output file, writing the header to a new or empty one
+func openOutput(path string) (*os.File, *selfplay.Writer, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	var w *selfplay.Writer
+	if info.Size() == 0 {
+		w, err = selfplay.NewWriter(f)
+	} else {
+		// check the header of the existing file before appending to it
+		if _, err = selfplay.NewReader(f); err == nil {
+			w = selfplay.AppendWriter(f)
+		}
+	}
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, w, nil
+}
+
+// returns the result of a game like "B+3.5"
+func result(g selfplay.Game) string {
+	switch g.Winner {
+	case engine.Black:
+		return fmt.Sprintf("B+%g", g.Score)
+	case engine.White:
+		return fmt.Sprintf("W+%g", -g.Score)
+	default:
+		return "draw"
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "selfplay: %v\n", err)
+	os.Exit(2)
+}
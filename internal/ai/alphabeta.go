@@ -0,0 +1,206 @@
+package ai
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/awesohame/gogo/internal/engine"
+)
+
+// AlphaBetaBot searches a fixed no. of plies with alpha-beta and scores the leaves with an Evaluator
+//
+// the search deepens one ply at a time and keeps the move of the deepest finished iteration,
+// so the budget can stop it at any point. with a node budget and no time limit it is deterministic
+type AlphaBetaBot struct {
+	Evaluator Evaluator // scores leaf positions for the player to move
+	MaxDepth  int       // deepest iteration in plies (if 0, until the budget runs out)
+	MaxNodes  int       // positions to visit per move (if 0, no limit)
+	TimeLimit float64   // time limit in sec (if 0, no limit)
+	Verbose   bool      // print search stats after each move
+}
+
+// AlphaBetaResult summarizes a finished search
+type AlphaBetaResult struct {
+	Move  engine.Move // chosen move, Point -1 for a pass
+	Score float64     // evaluation of the move for the player making it
+	Depth int         // plies of the deepest finished iteration
+	Nodes int         // positions visited
+}
+
+// NewAlphaBetaBot creates a bot searching 3 plies with the given evaluator
+func NewAlphaBetaBot(evaluator Evaluator) *AlphaBetaBot {
+	return &AlphaBetaBot{
+		Evaluator: evaluator,
+		MaxDepth:  3,
+		MaxNodes:  200000,
+		TimeLimit: 0, // no time lim by default
+	}
+}
+
+// state of one search
+type alphaBetaSearch struct {
+	bot     *AlphaBetaBot
+	best    map[uint64]engine.Point // best move of each searched position, tried first next iteration
+	aborted bool
+	searchBudget
+}
+
+// implements the Bot interface using alpha-beta search
+func (bot *AlphaBetaBot) SelectMove(board *engine.Board, color engine.Color) engine.Move {
+	return bot.Search(board, color).Move
+}
+
+// searches the position for the player to move and returns the chosen move with search stats
+func (bot *AlphaBetaBot) Search(board *engine.Board, color engine.Color) AlphaBetaResult {
+	s := &alphaBetaSearch{
+		bot:  bot,
+		best: make(map[uint64]engine.Point),
+
+		searchBudget: newSearchBudget(bot.MaxNodes, bot.TimeLimit),
+	}
+
+	moves := s.orderedMoves(board, color)
+	result := AlphaBetaResult{Move: moves[0]}
+	for depth := 1; bot.MaxDepth <= 0 || depth <= bot.MaxDepth; depth++ {
+		move, score, finished := s.searchRoot(board, color, moves, depth)
+		if finished || depth == 1 {
+			// a cut off first iteration still beats the static order
+			result.Move, result.Score = move, score
+		}
+		if !finished {
+			break
+		}
+		result.Depth = depth
+
+		// the best move leads the next iteration
+		moves = s.orderedMoves(board, color)
+		if bot.MaxDepth <= 0 && len(moves) == 1 {
+			break // nothing to choose between
+		}
+	}
+	result.Nodes = s.nodes
+
+	if bot.Verbose {
+		fmt.Printf("AlphaBeta: %d nodes, depth %d, selected move with score %.1f\n",
+			result.Nodes, result.Depth, result.Score)
+	}
+	return result
+}
+
+// searches every root move to the given depth, finished is false when the budget ran out first
+// in which case the best of the moves searched so far is returned
+func (s *alphaBetaSearch) searchRoot(board *engine.Board, color engine.Color, moves []engine.Move, depth int) (engine.Move, float64, bool) {
+	bestMove, bestScore := moves[0], math.Inf(-1)
+	for _, move := range moves {
+		score, ok := s.child(board, move, false, depth, bestScore, math.Inf(1))
+		if s.aborted {
+			return bestMove, bestScore, false
+		}
+		if ok && score > bestScore {
+			bestMove, bestScore = move, score
+		}
+	}
+	s.best[s.key(board, color)] = bestMove.Point
+	return bestMove, bestScore, true
+}
+
+// plays move and searches the result, returning the score for the player making the move
+// ok is false when the move repeats an earlier position
+func (s *alphaBetaSearch) child(board *engine.Board, move engine.Move, passed bool, depth int, alpha, beta float64) (float64, bool) {
+	next := board
+	if move.Point >= 0 {
+		var err error
+		if next, err = board.ApplyMove(move); err != nil {
+			return 0, false // superko
+		}
+	} else if passed {
+		// second pass in a row, the game is over
+		return s.bot.Evaluator.Evaluate(board, move.Color), true
+	}
+	return -s.negamax(next, opponentColor(move.Color), move.Point < 0, depth-1, -beta, -alpha), true
+}
+
+// returns the score of a position for the player to move, passed is whether the last move was a pass
+func (s *alphaBetaSearch) negamax(board *engine.Board, toMove engine.Color, passed bool, depth int, alpha, beta float64) float64 {
+	if s.outOfBudget() {
+		s.aborted = true
+		return 0
+	}
+	s.nodes++
+	if depth <= 0 {
+		return s.bot.Evaluator.Evaluate(board, toMove)
+	}
+
+	bestMove, bestScore := engine.Point(-1), math.Inf(-1)
+	for _, move := range s.orderedMoves(board, toMove) {
+		score, ok := s.child(board, move, passed, depth, alpha, beta)
+		if s.aborted {
+			return 0
+		}
+		if !ok {
+			continue
+		}
+		if score > bestScore {
+			bestMove, bestScore = move.Point, score
+		}
+		alpha = math.Max(alpha, score)
+		if alpha >= beta {
+			break
+		}
+	}
+	s.best[s.key(board, toMove)] = bestMove
+	return bestScore
+}
+
+// returns the table key of a position
+func (s *alphaBetaSearch) key(board *engine.Board, toMove engine.Color) uint64 {
+	if toMove == engine.White {
+		return board.Hash() ^ whiteToMoveKey
+	}
+	return board.Hash()
+}
+
+// returns the legal moves, best move of the last iteration first, then captures, escapes and
+// working ladders, other tactical moves, quiet moves and self-ataris, and a pass last
+// filling own eyes is left out
+func (s *alphaBetaSearch) orderedMoves(board *engine.Board, color engine.Color) []engine.Move {
+	hint, hasHint := s.best[s.key(board, color)]
+
+	type scored struct {
+		move  engine.Move
+		score int
+	}
+	candidates := make([]scored, 0, board.Width()*board.Height()+1)
+	for _, p := range board.LegalMoves(color) {
+		move := engine.Move{Point: p, Color: color}
+		if IsEyeFillingMove(board, move) {
+			continue
+		}
+		score := 0
+		tactic := ClassifyMove(board, move)
+		switch {
+		case hasHint && p == hint:
+			score = 4
+		case tactic.Has(TacticSelfAtari):
+			score = -1
+		case tactic&(TacticCapture|TacticEscape|TacticLadder) != 0:
+			score = 3
+		case tactic != TacticNone:
+			score = 2
+		}
+		candidates = append(candidates, scored{move, score})
+	}
+	passScore := -2
+	if hasHint && hint < 0 {
+		passScore = 4
+	}
+	candidates = append(candidates, scored{engine.Move{Point: -1, Color: color}, passScore})
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+
+	moves := make([]engine.Move, len(candidates))
+	for i, c := range candidates {
+		moves[i] = c.move
+	}
+	return moves
+}
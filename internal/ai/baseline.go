@@ -0,0 +1,109 @@
+package ai
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/awesohame/gogo/internal/engine"
+)
+
+// RandomBot plays a uniformly random legal move that does not fill its own eye
+type RandomBot struct {
+	rng *rand.Rand
+}
+
+// NewRandomBot creates a random bot, the same seed gives the same moves
+func NewRandomBot(seed int64) *RandomBot {
+	return &RandomBot{rng: rand.New(rand.NewSource(seed))}
+}
+
+// implements the Bot interface, passing when only eye fills are left
+func (bot *RandomBot) SelectMove(board *engine.Board, color engine.Color) engine.Move {
+	moves := candidateMoves(board, color)
+	if len(moves) == 0 {
+		return engine.Move{Point: -1, Color: color}
+	}
+	return moves[bot.rng.Intn(len(moves))]
+}
+
+// GreedyBot plays the move capturing the most stones, or else leaving its stones the most liberties
+// ties go to the first point in board order, so it always answers a position the same way
+type GreedyBot struct{}
+
+// NewGreedyBot creates a greedy bot
+func NewGreedyBot() *GreedyBot {
+	return &GreedyBot{}
+}
+
+// implements the Bot interface, passing when only eye fills are left
+func (bot *GreedyBot) SelectMove(board *engine.Board, color engine.Color) engine.Move {
+	best := engine.Move{Point: -1, Color: color}
+	bestCaptures, bestLibs := -1, -1
+	for _, move := range candidateMoves(board, color) {
+		_, result, err := board.ApplyMoveWithResult(move)
+		if err != nil {
+			continue // superko
+		}
+		captures := len(result.Captured)
+		if captures > bestCaptures || (captures == bestCaptures && result.Liberties > bestLibs) {
+			best, bestCaptures, bestLibs = move, captures, result.Liberties
+		}
+	}
+	return best
+}
+
+// HeuristicBot looks one move ahead and plays the move its Evaluator likes best
+type HeuristicBot struct {
+	Evaluator Evaluator // scores the position after each move for the player making it
+}
+
+// NewHeuristicBot creates a one-ply bot with the default HybridEvaluator
+func NewHeuristicBot() *HeuristicBot {
+	return &HeuristicBot{Evaluator: NewHybridEvaluator()}
+}
+
+// implements the Bot interface, passing when only eye fills are left
+func (bot *HeuristicBot) SelectMove(board *engine.Board, color engine.Color) engine.Move {
+	best := engine.Move{Point: -1, Color: color}
+	bestScore := math.Inf(-1)
+	for _, move := range candidateMoves(board, color) {
+		next, err := board.ApplyMove(move)
+		if err != nil {
+			continue // superko
+		}
+		if score := bot.Evaluator.Evaluate(next, color); score > bestScore {
+			best, bestScore = move, score
+		}
+	}
+	return best
+}
+
+// returns the legal moves of color that do not fill its own eyes, corner eyes included
+func candidateMoves(board *engine.Board, color engine.Color) []engine.Move {
+	points := board.LegalMoves(color)
+	moves := make([]engine.Move, 0, len(points))
+	for _, p := range points {
+		move := engine.Move{Point: p, Color: color}
+		if !IsEyeFillingMove(board, move) && !isCornerEye(board, p, color) {
+			moves = append(moves, move)
+		}
+	}
+	return moves
+}
+
+// reports whether p is a corner point with both neighbors of color
+// IsEyeFillingMove wants 3 friendly neighbors, which a corner never has
+func isCornerEye(board *engine.Board, p engine.Point, color engine.Color) bool {
+	onBoard := 0
+	for _, n := range board.Neighbors(p) {
+		switch board.ColorAt(n) {
+		case engine.Border:
+			continue
+		case color:
+			onBoard++
+		default:
+			return false
+		}
+	}
+	return onBoard == 2
+}
@@ -18,49 +18,28 @@ func EvaluatePosition(board *engine.Board) float64 {
 	return score
 }
 
-// checks if a move is imp (captures, prevent atari, inc liberties)
+// checks if a move is imp (captures, saves a group in atari, ataris, connects, cuts or starts a working ladder)
+// self-ataris never count, see ClassifyMove for the categories themselves
 func IsCriticalMove(board *engine.Board, move engine.Move) bool {
-	// Apply move and check immediate tactical consequences
-	newBoard, err := board.ApplyMove(move)
-	if err != nil {
-		return false
-	}
-
-	// Check if move captures stones
-	// Check if move saves a group in atari
-	// Check if move threatens opponent groups
-	// (simplified check - can be expanded)
-
-	_ = newBoard
-	return false
+	tactic := ClassifyMove(board, move)
+	return tactic != TacticNone && !tactic.Has(TacticSelfAtari)
 }
 
 // calcs territorial influence for a color
 // higher scores indicate stronger control
 func GetInfluenceScore(board *engine.Board, color engine.Color) float64 {
 	influence := 0.0
-	size := board.Size()
-
-	// count stones and weighted empty points nearby
-	for y := 1; y <= size; y++ {
-		for x := 1; x <= size; x++ {
-			point := board.ToPoint(x, y)
-			c := board.At(x, y)
-
-			if c == color {
-				influence += 1.0
-
-				// Add influence for nearby empty points
-				for _, n := range board.Neighbors(point) {
-					nx, ny := board.ToXY(n)
-					if nx >= 1 && nx <= size && ny >= 1 && ny <= size {
-						if board.At(nx, ny) == engine.Empty {
-							influence += 0.3
-						}
-					}
+
+	// count stones and weighted empty points nearby, a liberty counts once per stone touching it
+	for _, group := range board.Groups(color) {
+		influence += float64(group.Size())
+		group.Stones.ForEach(func(stone engine.Point) {
+			for _, n := range board.Neighbors(stone) {
+				if board.ColorAt(n) == engine.Empty {
+					influence += 0.3
 				}
 			}
-		}
+		})
 	}
 
 	return influence
@@ -69,12 +48,12 @@ func GetInfluenceScore(board *engine.Board, color engine.Color) float64 {
 // gives bonus for playing in corners/edges early
 func GetCornerProximityBonus(board *engine.Board, point engine.Point) float64 {
 	x, y := board.ToXY(point)
-	size := board.Size()
+	width, height := board.Width(), board.Height()
 
 	// dist from nearest corner
 	minDist := math.Min(
-		math.Min(float64(x-1), float64(size-x)),
-		math.Min(float64(y-1), float64(size-y)),
+		math.Min(float64(x-1), float64(width-x)),
+		math.Min(float64(y-1), float64(height-y)),
 	)
 
 	// higher bonus for corner/edge moves
@@ -89,26 +68,45 @@ func GetCornerProximityBonus(board *engine.Board, point engine.Point) float64 {
 func IsEyeFillingMove(board *engine.Board, move engine.Move) bool {
 	point := move.Point
 	color := move.Color
-	size := board.Size()
 
 	// must be empty
-	x, y := board.ToXY(point)
-	if board.At(x, y) != engine.Empty {
+	if board.ColorAt(point) != engine.Empty {
 		return false
 	}
 
 	// check if surrounded by friendly stones
-	neighbors := board.Neighbors(point)
 	friendlyCount := 0
+	for _, n := range board.Neighbors(point) {
+		switch board.ColorAt(n) {
+		case color:
+			friendlyCount++
+		case engine.Empty:
+			return false // not surrounded
+		}
+	}
 
-	for _, n := range neighbors {
-		nx, ny := board.ToXY(n)
-		if nx < 1 || nx > size || ny < 1 || ny > size {
-			continue
+	// likely an eye if 3 or more friendly neighbors, unless the move captures
+	// an enemy group in atari next to it
+	if friendlyCount < 3 {
+		return false
+	}
+	for _, n := range board.Neighbors(point) {
+		if c := board.ColorAt(n); c != color && c != engine.Border && board.Liberties(n) == 1 {
+			return false
 		}
+	}
+	return true
+}
+
+// IsEyeFillingMove for playout boards
+func isPlayoutEyeFill(board *engine.PlayoutBoard, point engine.Point, color engine.Color) bool {
+	if board.ColorAt(point) != engine.Empty {
+		return false
+	}
 
-		nColor := board.At(nx, ny)
-		switch nColor {
+	friendlyCount := 0
+	for _, n := range board.Neighbors(point) {
+		switch board.ColorAt(n) {
 		case color:
 			friendlyCount++
 		case engine.Empty:
@@ -119,3 +117,49 @@ func IsEyeFillingMove(board *engine.Board, move engine.Move) bool {
 	// likely an eye if 3 or more friendly neighbors
 	return friendlyCount >= 3
 }
+
+// checks if a move runs a group out of atari into a ladder that still captures it (wasted move)
+func IsFutileLadderEscape(board *engine.Board, move engine.Move) bool {
+	if !savesAtariGroup(board, move.Point, move.Color) {
+		return false
+	}
+
+	newBoard, err := board.ApplyMove(move)
+	if err != nil {
+		return false
+	}
+	libs := newBoard.Liberties(move.Point)
+	return libs <= 1 || (libs == 2 && newBoard.IsLadderCaptured(move.Point, opponentColor(move.Color)))
+}
+
+// IsFutileLadderEscape for playout boards, reads the ladder in place and restores the board
+func isPlayoutFutileEscape(board *engine.PlayoutBoard, point engine.Point, color engine.Color) bool {
+	if !savesAtariGroup(board, point, color) {
+		return false
+	}
+
+	if !board.Play(point, color) {
+		return false
+	}
+	libs := board.Liberties(point)
+	futile := libs <= 1 || (libs == 2 && board.IsLadderCaptured(point, opponentColor(color)))
+	board.Undo()
+	return futile
+}
+
+// liberty queries shared by Board and PlayoutBoard
+type libertyReader interface {
+	ColorAt(p engine.Point) engine.Color
+	Liberties(p engine.Point) int
+	Neighbors(p engine.Point) [4]engine.Point
+}
+
+// reports whether a point is the last liberty of a friendly group next to it
+func savesAtariGroup(board libertyReader, point engine.Point, color engine.Color) bool {
+	for _, n := range board.Neighbors(point) {
+		if board.ColorAt(n) == color && board.Liberties(n) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/awesohame/gogo/internal/engine"
@@ -11,11 +12,27 @@ import (
 
 // MCTSBot implements Monte Carlo Tree Search
 type MCTSBot struct {
-	MaxSimulations int       // no. of simulations to run
-	TimeLimit      float64   // time limit in sec (if 0, uses MaxSimulations)
-	ExplorationC   float64   // UCB exploration const (sqrt 2)
-	ReuseTree      bool      // whether to reuse tree between moves
-	lastRoot       *MCTSNode // root from previous move for tree reuse
+	MaxSimulations int        // no. of simulations to run
+	TimeLimit      float64    // time limit in sec (if 0, uses MaxSimulations)
+	ExplorationC   float64    // UCB exploration const (sqrt 2)
+	ReuseTree      bool       // whether to reuse tree between moves
+	Verbose        bool       // print search stats after each move
+	Rand           *rand.Rand // source of expansion order and playout moves, seeded from the clock if nil
+	lastRoot       *MCTSNode  // root from previous move for tree reuse
+}
+
+// SearchResult summarizes a finished search
+type SearchResult struct {
+	Move        engine.Move // chosen move, Point -1 for a pass
+	Simulations int         // simulations run by this search
+	Candidates  []Candidate // root moves, most visited first
+}
+
+// Candidate holds the search stats of a root move
+type Candidate struct {
+	Move    engine.Move
+	Visits  int
+	WinRate float64 // for the player making the move
 }
 
 // NewMCTSBot creates a new MCTS bot
@@ -25,12 +42,21 @@ func NewMCTSBot(simulations int) *MCTSBot {
 		TimeLimit:      0, // no time lim by default
 		ExplorationC:   math.Sqrt(2),
 		ReuseTree:      true, // tree reuse by default
+		Verbose:        true,
 	}
 }
 
 // implements the Bot interface using MCTS
 func (bot *MCTSBot) SelectMove(board *engine.Board, color engine.Color) engine.Move {
+	return bot.Search(board, color).Move
+}
+
+// runs a search for the player to move and returns the chosen move with root stats
+func (bot *MCTSBot) Search(board *engine.Board, color engine.Color) SearchResult {
 	previousColor := opponentColor(color)
+	if bot.Rand == nil {
+		bot.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
 
 	// Try to reuse tree from previous move
 	var root *MCTSNode
@@ -45,6 +71,7 @@ func (bot *MCTSBot) SelectMove(board *engine.Board, color engine.Color) engine.M
 	// If no reuse, create new root
 	if root == nil {
 		root = newMCTSNode(nil, engine.Move{Point: -1, Color: previousColor}, board, previousColor)
+		root.untriedMoves = mergeSymmetricMoves(board, root.untriedMoves)
 	}
 
 	startTime := time.Now()
@@ -67,19 +94,22 @@ func (bot *MCTSBot) SelectMove(board *engine.Board, color engine.Color) engine.M
 		}
 
 		// MCTS -> selection, expansion, simulation, backpropagation
-		node := root.selectNode(explorationC)
-		winner := node.simulate()
+		node := root.selectNode(explorationC, bot.Rand)
+		winner := node.simulate(bot.Rand)
 		node.backpropagate(winner)
 
 		simulations++
 	}
 
+	result := SearchResult{Simulations: simulations, Candidates: root.candidates()}
+
 	// choose the best move based on visit count
 	bestChild := root.bestChild(0) // 0 means no exploration
 	if bestChild == nil {
 		// pass when no legal moves
 		bot.lastRoot = nil
-		return engine.Move{Point: -1, Color: color}
+		result.Move = engine.Move{Point: -1, Color: color}
+		return result
 	}
 
 	// Save tree for reuse
@@ -87,10 +117,54 @@ func (bot *MCTSBot) SelectMove(board *engine.Board, color engine.Color) engine.M
 		bot.lastRoot = bestChild
 	}
 
-	fmt.Printf("MCTS: %d simulations, selected move with %d visits (%.1f%% win rate)\n",
-		simulations, bestChild.visits, 100.0*bestChild.wins/float64(bestChild.visits))
+	if bot.Verbose {
+		fmt.Printf("MCTS: %d simulations, selected move with %d visits (%.1f%% win rate)\n",
+			simulations, bestChild.visits, 100.0*bestChild.wins/float64(bestChild.visits))
+	}
+
+	result.Move = bestChild.move
+	return result
+}
 
-	return bestChild.move
+// returns the stats of all children, most visited first
+func (n *MCTSNode) candidates() []Candidate {
+	candidates := make([]Candidate, 0, len(n.children))
+	for _, child := range n.children {
+		c := Candidate{Move: child.move, Visits: child.visits}
+		if child.visits > 0 {
+			c.WinRate = child.wins / float64(child.visits)
+		}
+		candidates = append(candidates, c)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Visits > candidates[j].Visits
+	})
+	return candidates
+}
+
+// keeps one move of each set of moves that lead to the same position up to symmetry,
+// so on symmetric positions like the empty board each distinct move is searched once
+func mergeSymmetricMoves(board *engine.Board, moves []engine.Move) []engine.Move {
+	symmetries := board.Symmetries()
+	if len(symmetries) == 1 {
+		return moves
+	}
+
+	merged := moves[:0]
+	for _, move := range moves {
+		// the representative is the lowest point of the move's orbit
+		representative := true
+		for _, s := range symmetries[1:] {
+			if board.TransformPoint(move.Point, s) < move.Point {
+				representative = false
+				break
+			}
+		}
+		if representative {
+			merged = append(merged, move)
+		}
+	}
+	return merged
 }
 
 // attempts to find a child node matching the current board
@@ -114,7 +188,7 @@ type MCTSNode struct {
 	color    engine.Color // color of the player who just moved to reach this state
 
 	visits       int
-	wins         float64 // wins from the perspective of the parent's color
+	wins         float64 // wins for the player who moved into this node
 	untriedMoves []engine.Move
 }
 
@@ -137,25 +211,27 @@ func newMCTSNode(parent *MCTSNode, move engine.Move, board *engine.Board, color
 	return node
 }
 
-// faster legal move generation that doesn't validate every move
+// legal move generation that skips eye fills and orders likely good points first
+// tactical moves come first: captures, escapes and working ladders, then ataris, connections and cuts
 func getLegalMovesFast(board *engine.Board, color engine.Color) []engine.Move {
 	moves := make([]engine.Move, 0, 40)
-	size := board.Size()
+	width, height := board.Width(), board.Height()
 
-	// prioritize center and corner/edge positions early
+	// prioritize urgent tactics, then other tactics with center and corner/edge positions
+	urgentMoves := make([]engine.Move, 0, 4)
 	priorityMoves := make([]engine.Move, 0, 20)
 	normalMoves := make([]engine.Move, 0, 20)
+	var badMoves []engine.Move
 
-	center := size / 2
+	centerX, centerY := width/2, height/2
 
-	for y := 1; y <= size; y++ {
-		for x := 1; x <= size; x++ {
-			// only consider empty points
-			if board.At(x, y) != engine.Empty {
+	for y := 1; y <= height; y++ {
+		for x := 1; x <= width; x++ {
+			// only consider legal points, so expansion never applies an illegal move
+			point := board.ToPoint(x, y)
+			if !board.IsLegal(point, color) {
 				continue
 			}
-
-			point := board.ToPoint(x, y)
 			move := engine.Move{Point: point, Color: color}
 
 			// quick heuristic: skip obvious eye fills
@@ -163,21 +239,31 @@ func getLegalMovesFast(board *engine.Board, color engine.Color) []engine.Move {
 				continue
 			}
 
-			// prioritize center area and corners/edges
-			distFromCenter := abs(x-center) + abs(y-center)
-			isEdge := x == 1 || x == size || y == 1 || y == size
-
-			if distFromCenter <= 2 || isEdge {
+			distFromCenter := abs(x-centerX) + abs(y-centerY)
+			isEdge := x == 1 || x == width || y == 1 || y == height
+
+			// self-ataris and lost ladders go last, the classifier already read the ladder
+			// so saving an atari group without it counting as an escape means running a lost one
+			tactic := ClassifyMove(board, move)
+			futile := !tactic.Has(TacticEscape) && savesAtariGroup(board, point, color)
+			switch {
+			case tactic.Has(TacticSelfAtari) || futile:
+				badMoves = append(badMoves, move)
+			case tactic&(TacticCapture|TacticEscape|TacticLadder) != 0:
+				urgentMoves = append(urgentMoves, move)
+			case tactic != TacticNone || distFromCenter <= 2 || isEdge:
 				priorityMoves = append(priorityMoves, move)
-			} else {
+			default:
 				normalMoves = append(normalMoves, move)
 			}
 		}
 	}
 
-	// return priority moves first, then normal moves
+	// return urgent moves first, then priority moves, normal moves and bad moves
+	moves = append(moves, urgentMoves...)
 	moves = append(moves, priorityMoves...)
 	moves = append(moves, normalMoves...)
+	moves = append(moves, badMoves...)
 
 	return moves
 }
@@ -190,7 +276,7 @@ func abs(x int) int {
 }
 
 // traverses the tree using UCB1 until a leaf node
-func (n *MCTSNode) selectNode(explorationC float64) *MCTSNode {
+func (n *MCTSNode) selectNode(explorationC float64, rng *rand.Rand) *MCTSNode {
 	current := n
 
 	for len(current.untriedMoves) == 0 && len(current.children) > 0 {
@@ -199,20 +285,20 @@ func (n *MCTSNode) selectNode(explorationC float64) *MCTSNode {
 
 	// if there are untried moves, expand
 	if len(current.untriedMoves) > 0 {
-		return current.expand()
+		return current.expand(rng)
 	}
 
 	return current
 }
 
 // adds a new child node for an untried move
-func (n *MCTSNode) expand() *MCTSNode {
+func (n *MCTSNode) expand(rng *rand.Rand) *MCTSNode {
 	if len(n.untriedMoves) == 0 {
 		return n
 	}
 
 	// pick random untried move
-	idx := rand.Intn(len(n.untriedMoves))
+	idx := rng.Intn(len(n.untriedMoves))
 	move := n.untriedMoves[idx]
 
 	// remove from untried moves (swap with last for efficiency)
@@ -224,7 +310,7 @@ func (n *MCTSNode) expand() *MCTSNode {
 	if err != nil {
 		// move became illegal, try another if available
 		if len(n.untriedMoves) > 0 {
-			return n.expand()
+			return n.expand(rng)
 		}
 		return n
 	}
@@ -237,9 +323,16 @@ func (n *MCTSNode) expand() *MCTSNode {
 }
 
 // runs a random playout from this node and returns the winner
-func (n *MCTSNode) simulate() engine.Color {
-	board := n.board
-	currentColor := opponentColor(n.color)
+func (n *MCTSNode) simulate(rng *rand.Rand) engine.Color {
+	return playout(n.board, n.move.Point, opponentColor(n.color), 0, rng)
+}
+
+// plays random moves from start, answering tactics, until both sides pass and returns the winner
+// under komi. last is the move that led to start, -1 for none or a pass
+func playout(start *engine.Board, last engine.Point, toMove engine.Color, komi float64, rng *rand.Rand) engine.Color {
+	// play out on a mutable copy, much cheaper than a new board per move
+	board := engine.NewPlayoutBoard(start)
+	currentColor := toMove
 	passCount := 0
 	maxMoves := 150
 	moveCount := 0
@@ -258,36 +351,34 @@ func (n *MCTSNode) simulate() engine.Color {
 			}
 		}
 
-		// fast legal move generation with caching
-		legalMoves := getFastLegalMoves(board, currentColor, 25) // reduced from 30
-
-		if len(legalMoves) == 0 {
+		// answer the last move tactically, else pick a random legal move that doesn't fill an own eye
+		point, ok := playoutReply(board, last, currentColor)
+		if !ok {
+			point, ok = randomPlayoutMove(board, currentColor, rng)
+		}
+		if !ok {
 			passCount++
 			if passCount >= 2 {
 				// both players passed, game over
 				break
 			}
+			board.Pass(currentColor)
 			currentColor = opponentColor(currentColor)
+			last = -1
 			continue
 		}
 
 		passCount = 0
 		moveCount++
 
-		// pick a random move
-		move := legalMoves[rand.Intn(len(legalMoves))]
-		newBoard, err := board.ApplyMove(move)
-		if err != nil {
-			//skip invalid move
-			continue
-		}
+		board.Play(point, currentColor)
+		last = point
 
-		board = newBoard
 		currentColor = opponentColor(currentColor)
 	}
 
 	// get winner by score
-	_, _, winner := board.CalculateScoreWithKomi(0)
+	_, _, winner := board.CalculateScoreWithKomi(komi)
 
 	return winner
 }
@@ -299,11 +390,10 @@ func (n *MCTSNode) backpropagate(winner engine.Color) {
 	for current != nil {
 		current.visits++
 
-		// Update wins from parent's perspective
+		// Update wins for the player choosing this node at the parent (who moved into it)
 		if current.parent != nil {
-			parentColor := current.parent.color
 			switch winner {
-			case parentColor:
+			case current.color:
 				current.wins += 1.0
 			case engine.Empty:
 				current.wins += 0.5 // draw
@@ -346,73 +436,32 @@ func (n *MCTSNode) ucb1Score(explorationC float64) float64 {
 	return exploitation + exploration
 }
 
-// returns a limited set of legal moves for fast simulation
-func getFastLegalMoves(board *engine.Board, color engine.Color, maxMoves int) []engine.Move {
-	moves := make([]engine.Move, 0, maxMoves)
-	size := board.Size()
-
-	// use map for correct indexing with internal board points
-	tried := make(map[engine.Point]bool, size*size)
-	attempts := 0
-	maxAttempts := min(size*size, maxMoves*3) // limit attempts
+// returns a random legal move for playouts, skipping own eyes
+// scans the board from a random starting point so no move list is built
+func randomPlayoutMove(board *engine.PlayoutBoard, color engine.Color, rng *rand.Rand) (engine.Point, bool) {
+	width := board.Width()
+	total := width * board.Height()
+	start := rng.Intn(total)
 
-	for len(moves) < maxMoves && attempts < maxAttempts {
-		// random point
-		x := rand.Intn(size) + 1
-		y := rand.Intn(size) + 1
-		point := board.ToPoint(x, y)
-
-		if tried[point] {
-			attempts++
-			continue
-		}
-		tried[point] = true
-		attempts++
+	for i := 0; i < total; i++ {
+		idx := (start + i) % total
+		point := board.ToPoint(idx%width+1, idx/width+1)
 
 		// only consider empty points
-		if board.At(x, y) != engine.Empty {
+		if board.ColorAt(point) != engine.Empty {
 			continue
 		}
 
-		// dont fill own eyes (skip check in late game for speed)
-		if len(moves) < maxMoves/2 && IsEyeFillingMove(board, engine.Move{Point: point, Color: color}) {
+		// dont fill own eyes
+		if isPlayoutEyeFill(board, point, color) {
 			continue
 		}
 
-		move := engine.Move{Point: point, Color: color}
-
-		// try to apply move
-		_, err := board.ApplyMove(move)
-		if err == nil {
-			moves = append(moves, move)
+		// dont run ladders that are already lost
+		if board.IsLegal(point, color) && !isPlayoutFutileEscape(board, point, color) {
+			return point, true
 		}
 	}
 
-	// if we didnt find enough moves, use systematic search
-	if len(moves) < 5 {
-		for y := 1; y <= size && len(moves) < maxMoves; y++ {
-			for x := 1; x <= size && len(moves) < maxMoves; x++ {
-				point := board.ToPoint(x, y)
-
-				if tried[point] || board.At(x, y) != engine.Empty {
-					continue
-				}
-
-				move := engine.Move{Point: point, Color: color}
-				_, err := board.ApplyMove(move)
-				if err == nil {
-					moves = append(moves, move)
-				}
-			}
-		}
-	}
-
-	return moves
-}
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
+	return -1, false
 }
@@ -0,0 +1,273 @@
+package ai
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/awesohame/gogo/internal/engine"
+)
+
+// PolicyValueNet evaluates a position for the side to move
+// policy holds one probability per point, row by row from the top left, and the pass last,
+// value is the expected result in [-1, 1]. history holds earlier positions, the most recent first,
+// ko is the point toMove may not retake at, -1 for none, which after a pass differs from board's
+type PolicyValueNet interface {
+	PolicyValue(board *engine.Board, toMove engine.Color, history []*engine.Board, ko engine.Point) (policy []float32, value float32, err error)
+}
+
+// no. of earlier positions handed to the net, more than any input needs
+const puctHistory = 8
+
+// PUCTBot searches like MCTSBot but guided by a policy/value net, as in AlphaZero
+// the policy gives the priors of the moves and the value head replaces or is blended with playouts
+type PUCTBot struct {
+	Net            PolicyValueNet
+	MaxSimulations int        // no. of simulations to run
+	TimeLimit      float64    // time limit in sec (if 0, uses MaxSimulations)
+	CPuct          float64    // weight of the prior against the value (1.5)
+	ValueWeight    float64    // share of the net value in a leaf, the rest is a playout (1 = net only)
+	Komi           float64    // komi used to score playouts and lines ending in two passes
+	Verbose        bool       // print search stats after each move
+	Rand           *rand.Rand // source of playout moves, seeded from the clock if nil
+}
+
+// creates a PUCT bot that uses net for priors and values only
+func NewPUCTBot(net PolicyValueNet, simulations int) *PUCTBot {
+	return &PUCTBot{
+		Net:            net,
+		MaxSimulations: simulations,
+		CPuct:          1.5,
+		ValueWeight:    1,
+		Komi:           7.5,
+	}
+}
+
+// implements the Bot interface using PUCT search, the net sees no positions before board
+func (bot *PUCTBot) SelectMove(board *engine.Board, color engine.Color) engine.Move {
+	return bot.Search(board, color).Move
+}
+
+// plays like SelectMove, handing the net the positions of the game before board, the most recent first
+func (bot *PUCTBot) SelectMoveWithHistory(board *engine.Board, color engine.Color, history []*engine.Board) engine.Move {
+	return bot.SearchWithHistory(board, color, history).Move
+}
+
+// node of the PUCT tree, stats are for the player who made move
+type puctNode struct {
+	parent   *puctNode
+	move     engine.Move
+	board    *engine.Board // position after move
+	passes   int           // passes in a row ending with move
+	ko       engine.Point  // point the side to move may not retake at, -1 after a pass
+	prior    float64
+	visits   int
+	valueSum float64 // sum of values in [-1, 1]
+	children []*puctNode
+	expanded bool
+	before   []*engine.Board // positions of the game before the root, the most recent first, root only
+}
+
+// runs a search for the player to move and returns the chosen move with root stats
+// if the net fails on the root position the search falls back to a playout MCTSBot
+func (bot *PUCTBot) Search(board *engine.Board, color engine.Color) SearchResult {
+	return bot.SearchWithHistory(board, color, nil)
+}
+
+// runs a search like Search, history holds the positions of the game before board, the most recent first
+func (bot *PUCTBot) SearchWithHistory(board *engine.Board, color engine.Color, history []*engine.Board) SearchResult {
+	if bot.Rand == nil {
+		bot.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	root := &puctNode{move: engine.Move{Point: -1, Color: opponentColor(color)}, board: board, ko: board.KoPoint(), before: history}
+	if _, err := bot.expand(root); err != nil {
+		if bot.Verbose {
+			fmt.Printf("PUCT: %v, searching with playouts\n", err)
+		}
+		fallback := NewMCTSBot(bot.MaxSimulations)
+		fallback.TimeLimit = bot.TimeLimit
+		fallback.ReuseTree = false
+		fallback.Verbose = bot.Verbose
+		fallback.Rand = bot.Rand
+		return fallback.Search(board, color)
+	}
+
+	startTime := time.Now()
+	simulations := 0
+	for {
+		if bot.TimeLimit > 0 {
+			if time.Since(startTime).Seconds() >= bot.TimeLimit {
+				break
+			}
+		} else if simulations >= bot.MaxSimulations {
+			break
+		}
+
+		node := root
+		for node.expanded && node.passes < 2 {
+			node = node.selectChild(bot.CPuct)
+		}
+
+		// value for the side to move at node
+		var value float64
+		if node.passes >= 2 {
+			value = bot.scoreValue(node)
+		} else {
+			value, _ = bot.expand(node)
+		}
+		node.backpropagate(value)
+
+		simulations++
+	}
+
+	result := SearchResult{Simulations: simulations, Candidates: root.candidates()}
+	result.Move = result.Candidates[0].Move
+	if bot.Verbose {
+		best := result.Candidates[0]
+		fmt.Printf("PUCT: %d simulations, selected move with %d visits (%.1f%% win rate)\n",
+			simulations, best.Visits, 100*best.WinRate)
+	}
+	return result
+}
+
+// adds the children of node with the net's priors and returns the value for the side to move
+// a net error after the root leaves node unexpanded and falls back to a playout
+func (bot *PUCTBot) expand(node *puctNode) (float64, error) {
+	toMove := opponentColor(node.move.Color)
+	policy, value, err := bot.Net.PolicyValue(node.board, toMove, node.history(), node.ko)
+	width, height := node.board.Width(), node.board.Height()
+	if err == nil && len(policy) != width*height+1 {
+		err = fmt.Errorf("net gave %d move probabilities for a %dx%d board", len(policy), width, height)
+	}
+	if err != nil {
+		return bot.playoutValue(node, toMove), err
+	}
+
+	// the priors of legal moves are renormalised, the pass is always allowed and lifts the ko
+	pass := &puctNode{
+		parent: node,
+		move:   engine.Move{Point: -1, Color: toMove},
+		board:  node.board,
+		passes: node.passes + 1,
+		ko:     -1,
+		prior:  float64(policy[width*height]),
+	}
+	total := pass.prior
+	node.children = append(node.children, pass)
+	for _, p := range node.board.LegalMoves(toMove) {
+		move := engine.Move{Point: p, Color: toMove}
+		if IsEyeFillingMove(node.board, move) {
+			continue
+		}
+		next, err := node.board.ApplyMove(move)
+		if err != nil {
+			continue
+		}
+		x, y := node.board.ToXY(p)
+		child := &puctNode{parent: node, move: move, board: next, ko: next.KoPoint(), prior: float64(policy[(y-1)*width+x-1])}
+		total += child.prior
+		node.children = append(node.children, child)
+	}
+	for _, child := range node.children {
+		if total > 0 {
+			child.prior /= total
+		} else {
+			child.prior = 1 / float64(len(node.children))
+		}
+	}
+	node.expanded = true
+
+	v := float64(value)
+	if bot.ValueWeight < 1 {
+		v = bot.ValueWeight*v + (1-bot.ValueWeight)*bot.playoutValue(node, toMove)
+	}
+	return v, nil
+}
+
+// plays out the position of node and returns 1 if toMove wins, -1 if it loses, 0 for a draw
+func (bot *PUCTBot) playoutValue(node *puctNode, toMove engine.Color) float64 {
+	return resultValue(playout(node.board, node.move.Point, toMove, bot.Komi, bot.Rand), toMove)
+}
+
+// scores a line ended by two passes for the side to move at node
+func (bot *PUCTBot) scoreValue(node *puctNode) float64 {
+	_, _, winner := node.board.CalculateScoreWithKomi(bot.Komi)
+	return resultValue(winner, opponentColor(node.move.Color))
+}
+
+// returns the value of a game won by winner for color
+func resultValue(winner, color engine.Color) float64 {
+	switch winner {
+	case color:
+		return 1
+	case engine.Empty:
+		return 0
+	default:
+		return -1
+	}
+}
+
+// returns the positions before node, the most recent first, passes add none
+// the tree runs out at the root, the game before it goes on from there
+func (n *puctNode) history() []*engine.Board {
+	var boards []*engine.Board
+	current := n
+	for ; current.parent != nil && len(boards) < puctHistory; current = current.parent {
+		if current.move.Point >= 0 {
+			boards = append(boards, current.parent.board)
+		}
+	}
+	if current.parent == nil {
+		boards = append(boards, current.before[:min(len(current.before), puctHistory-len(boards))]...)
+	}
+	return boards
+}
+
+// returns the child with the highest Q + U, unvisited children count as Q = 0
+func (n *puctNode) selectChild(cPuct float64) *puctNode {
+	var best *puctNode
+	bestScore := math.Inf(-1)
+	scale := cPuct * math.Sqrt(float64(max(n.visits, 1)))
+	for _, child := range n.children {
+		score := child.q() + scale*child.prior/float64(1+child.visits)
+		if score > bestScore {
+			best, bestScore = child, score
+		}
+	}
+	return best
+}
+
+// returns the mean value for the player who made the move, 0 if never visited
+func (n *puctNode) q() float64 {
+	if n.visits == 0 {
+		return 0
+	}
+	return n.valueSum / float64(n.visits)
+}
+
+// adds a value for the side to move at n to n and its ancestors
+func (n *puctNode) backpropagate(value float64) {
+	for current := n; current != nil; current = current.parent {
+		value = -value // the player who moved into current is the other side
+		current.visits++
+		current.valueSum += value
+	}
+}
+
+// returns the stats of all children, most visited first, ties to the higher prior
+func (n *puctNode) candidates() []Candidate {
+	sorted := append([]*puctNode(nil), n.children...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].visits != sorted[j].visits {
+			return sorted[i].visits > sorted[j].visits
+		}
+		return sorted[i].prior > sorted[j].prior
+	})
+	candidates := make([]Candidate, len(sorted))
+	for i, child := range sorted {
+		candidates[i] = Candidate{Move: child.move, Visits: child.visits, WinRate: (child.q() + 1) / 2}
+	}
+	return candidates
+}
@@ -0,0 +1,30 @@
+package ai
+
+import "time"
+
+// keys mixed into position hashes so the side to move and a pending pass get separate table entries
+const (
+	whiteToMoveKey = 0x9e3779b97f4a7c15
+	passedKey      = 0xc2b2ae3d27d4eb4f
+)
+
+// searchBudget counts the nodes of a search and tells when its node or time limit is reached
+type searchBudget struct {
+	maxNodes  int     // if 0, no limit
+	timeLimit float64 // in sec, if 0 no limit
+	start     time.Time
+	nodes     int
+}
+
+// starts the clock of a budget
+func newSearchBudget(maxNodes int, timeLimit float64) searchBudget {
+	return searchBudget{maxNodes: maxNodes, timeLimit: timeLimit, start: time.Now()}
+}
+
+// reports whether the node or time budget is used up, checking the clock every 1024 nodes
+func (b *searchBudget) outOfBudget() bool {
+	if b.maxNodes > 0 && b.nodes >= b.maxNodes {
+		return true
+	}
+	return b.timeLimit > 0 && b.nodes%1024 == 0 && time.Since(b.start).Seconds() >= b.timeLimit
+}
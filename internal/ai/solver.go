@@ -0,0 +1,630 @@
+package ai
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/awesohame/gogo/internal/engine"
+)
+
+// KoRule selects which repetitions the exact solver forbids
+type KoRule int
+
+const (
+	PositionalSuperko  KoRule = iota // no move may recreate an earlier board position
+	SituationalSuperko               // no move may recreate an earlier position with the same player to move
+)
+
+func (r KoRule) String() string {
+	switch r {
+	case PositionalSuperko:
+		return "positional superko"
+	case SituationalSuperko:
+		return "situational superko"
+	default:
+		return "unknown"
+	}
+}
+
+// largest board side the exact solver accepts
+const maxSolverSize = 5
+
+// ExactSolver finds the optimal score of tiny boards
+//
+// the game ends after two passes in a row and is scored by area, every stone on the board
+// counting as alive (Tromp-Taylor). the solver proves bounds on the score by searching whether
+// black can force at least a given area, answering win, loss or unknown when lines reach the
+// depth limit. the limit grows one ply at a time and the bounds close in by bisection until
+// they meet, which makes the score exact, or the budget runs out. proven positions are shared
+// between iterations, thresholds and symmetric positions. repetitions are checked against the
+// line being searched, and only the first move against the game played before the root
+type ExactSolver struct {
+	Komi      float64 // added to white's score
+	KoRule    KoRule
+	MaxNodes  int     // positions to visit before giving up (if 0, no limit)
+	TimeLimit float64 // time limit in sec (if 0, no limit)
+}
+
+// ExactResult holds the outcome of a solve
+type ExactResult struct {
+	Score float64       // black minus white with komi under best play, positive when black wins, Lower if not exact
+	Lower float64       // proven lower bound of the score
+	Upper float64       // proven upper bound of the score
+	Exact bool          // whether the bounds met
+	Depth int           // depth limit of the last iteration
+	PV    []engine.Move // line proving the bound of the side to move, passes have Point -1
+	Nodes int           // positions visited
+}
+
+// NewExactSolver creates a solver for the given komi with positional superko and no budget
+func NewExactSolver(komi float64) *ExactSolver {
+	return &ExactSolver{
+		Komi:   komi,
+		KoRule: PositionalSuperko,
+	}
+}
+
+// outcome of a threshold search for the side to move
+type exactProof int8
+
+const (
+	proofUnknown exactProof = iota // a line reached the depth limit
+	proofWin
+	proofLoss
+)
+
+// stored search at one threshold, proofs go to the bounds of the position
+type exactEntry struct {
+	depth     int          // remaining plies the result was searched with, 0 if it was proven
+	minStones int          // fewest stones of any position the result rests on
+	dependent bool         // the result depends on the line it was searched on, only best is of use
+	best      engine.Point // move that proved or came closest, in the orientation of the key, -1 for a pass
+	hasBest   bool
+}
+
+// proven bounds of black's area at a position, each with the fewest stones of any position it rests on
+type exactBounds struct {
+	lower, upper             int
+	lowerStones, upperStones int
+}
+
+// result of searching a position
+type exactValue struct {
+	proof     exactProof
+	dependsOn int // earliest position of the line the result depends on, above the current one it depends on the line
+	minStones int // fewest stones of any position the result rests on
+}
+
+// state of one Solve call
+type exactSearch struct {
+	solver     *ExactSolver
+	board      *engine.PlayoutBoard
+	root       *engine.Board
+	symmetries []engine.Symmetry                           // symmetries that keep the board dimensions
+	stoneKeys  [2][maxSolverSize * maxSolverSize][8]uint64 // key of a stone at each point under each symmetry
+	table      map[uint64]exactEntry                       // by position and threshold
+	bounds     map[uint64]exactBounds                      // by position, shared between thresholds
+	path       map[uint64]int                              // positions on the line being searched, to their index in stones plus one
+	stones     []int                                       // stone counts along the line, the current position last
+	maxBack    []int                                       // running maximum of stones
+	threshold  int                                         // area black must reach in the current search
+	aborted    bool
+	searchBudget
+}
+
+// solves the position with toMove to play, boards larger than 5x5 are refused
+func (s *ExactSolver) Solve(board *engine.Board, toMove engine.Color) (ExactResult, error) {
+	if board.Width() > maxSolverSize || board.Height() > maxSolverSize {
+		return ExactResult{}, fmt.Errorf("exact solving needs a board of at most %dx%d, got %dx%d",
+			maxSolverSize, maxSolverSize, board.Width(), board.Height())
+	}
+
+	search := &exactSearch{
+		solver:     s,
+		board:      engine.NewPlayoutBoard(board),
+		root:       board,
+		symmetries: engine.AllSymmetries[:],
+		table:      make(map[uint64]exactEntry),
+		bounds:     make(map[uint64]exactBounds),
+		path:       make(map[uint64]int),
+
+		searchBudget: newSearchBudget(s.MaxNodes, s.TimeLimit),
+	}
+	if board.Width() != board.Height() {
+		search.symmetries = []engine.Symmetry{engine.Identity, engine.Rotate180, engine.FlipHorizontal, engine.FlipVertical}
+	}
+	for y := 1; y <= board.Height(); y++ {
+		for x := 1; x <= board.Width(); x++ {
+			for i, sym := range search.symmetries {
+				tx, ty := sym.TransformXY(x, y, board.Width(), board.Height())
+				for c := range search.stoneKeys {
+					search.stoneKeys[c][(y-1)*board.Width()+x-1][i] = exactKeys[c][(ty-1)*maxSolverSize+tx-1]
+				}
+			}
+		}
+	}
+	search.pushStones(countStones(search.board))
+	search.path[search.pathKey(toMove)] = len(search.stones)
+
+	// black's area is proven to be in [lower, upper]
+	points := board.Width() * board.Height()
+	lower, upper := -points, points
+	depth := 0
+	for lower < upper && !search.aborted {
+		depth++
+		for lower < upper {
+			t := lower + (upper-lower+1)/2
+			proof := search.prove(toMove, t, depth)
+			if search.aborted || proof == proofUnknown {
+				break
+			}
+			if (proof == proofWin) == (toMove == engine.Black) {
+				lower = t
+			} else {
+				upper = t - 1
+			}
+		}
+	}
+
+	result := ExactResult{
+		Score: float64(lower) - s.Komi,
+		Lower: float64(lower) - s.Komi,
+		Upper: float64(upper) - s.Komi,
+		Exact: lower == upper,
+		Depth: depth,
+		Nodes: search.nodes,
+	}
+	// the side to move proved its bound with the search at this threshold
+	if toMove == engine.Black {
+		result.PV = search.principalVariation(toMove, lower)
+	} else {
+		result.PV = search.principalVariation(toMove, upper+1)
+	}
+	return result, nil
+}
+
+// searches whether black reaches area t, the result is for the side to move
+func (s *exactSearch) prove(toMove engine.Color, t, depth int) exactProof {
+	s.threshold = t
+	return s.search(toMove, 0, depth).proof
+}
+
+// returns the key of the current position and the symmetry that maps it to the key's orientation
+// symmetric positions share a key
+func (s *exactSearch) key(toMove engine.Color, passes int) (uint64, engine.Symmetry) {
+	width, height := s.board.Width(), s.board.Height()
+	var hashes [8]uint64
+	for y := 1; y <= height; y++ {
+		for x := 1; x <= width; x++ {
+			c := s.board.ColorAt(s.board.ToPoint(x, y))
+			if c != engine.Black && c != engine.White {
+				continue
+			}
+			keys := &s.stoneKeys[c-engine.Black][(y-1)*width+x-1]
+			for i := range s.symmetries {
+				hashes[i] ^= keys[i]
+			}
+		}
+	}
+	best := 0
+	for i := range s.symmetries {
+		if hashes[i] < hashes[best] {
+			best = i
+		}
+	}
+
+	key := hashes[best]
+	if toMove == engine.White {
+		key ^= whiteToMoveKey
+	}
+	if passes > 0 {
+		key ^= passedKey
+	}
+	return key, s.symmetries[best]
+}
+
+// returns the table key of a position key at the current threshold
+func (s *exactSearch) tableKey(key uint64) uint64 {
+	return key ^ uint64(s.threshold+maxSolverSize*maxSolverSize+1)*thresholdKey
+}
+
+// keys of black and white stones at each point for the position keys, and the multiplier of the threshold
+var (
+	exactKeys    [2][maxSolverSize * maxSolverSize]uint64
+	thresholdKey uint64 = 0xbf58476d1ce4e5b9
+)
+
+func init() {
+	rng := rand.New(rand.NewSource(0x5eed))
+	for c := range exactKeys {
+		for i := range exactKeys[c] {
+			exactKeys[c][i] = rng.Uint64()
+		}
+	}
+}
+
+// maps a move between the board and the orientation of a table key
+func (s *exactSearch) toKeyOrientation(p engine.Point, sym engine.Symmetry) engine.Point {
+	if p < 0 {
+		return p
+	}
+	x, y := s.board.ToXY(p)
+	x, y = sym.TransformXY(x, y, s.board.Width(), s.board.Height())
+	return s.board.ToPoint(x, y)
+}
+
+// returns the repetition key of the current position under the ko rule
+func (s *exactSearch) pathKey(toMove engine.Color) uint64 {
+	if s.solver.KoRule == SituationalSuperko && toMove == engine.White {
+		return s.board.Hash() ^ whiteToMoveKey
+	}
+	return s.board.Hash()
+}
+
+// adds the stone count of a position joining the line
+func (s *exactSearch) pushStones(n int) {
+	max := n
+	if len(s.maxBack) > 0 && s.maxBack[len(s.maxBack)-1] > max {
+		max = s.maxBack[len(s.maxBack)-1]
+	}
+	s.stones = append(s.stones, n)
+	s.maxBack = append(s.maxBack, max)
+}
+
+// removes the last position of the line
+func (s *exactSearch) popStones() {
+	s.stones = s.stones[:len(s.stones)-1]
+	s.maxBack = s.maxBack[:len(s.maxBack)-1]
+}
+
+// reports whether a stored entry holds on the current line: every position it rests on
+// has more stones than any earlier position of the line, so none of them can be a repetition here
+func (s *exactSearch) reusable(minStones int) bool {
+	return len(s.maxBack) < 2 || minStones > s.maxBack[len(s.maxBack)-2]
+}
+
+// records that black reaches the current threshold at the position of key, or fails to if !reached
+// a bound resting on fewer stones replaces an equal one, it holds on fewer lines
+func (s *exactSearch) addBound(key uint64, reached bool, minStones int) {
+	b, ok := s.bounds[key]
+	if !ok {
+		b = exactBounds{lower: math.MinInt, upper: math.MaxInt, lowerStones: math.MaxInt, upperStones: math.MaxInt}
+	}
+	if reached {
+		if s.threshold > b.lower || (s.threshold == b.lower && minStones > b.lowerStones) {
+			b.lower, b.lowerStones = s.threshold, minStones
+		}
+	} else if s.threshold-1 < b.upper || (s.threshold-1 == b.upper && minStones > b.upperStones) {
+		b.upper, b.upperStones = s.threshold-1, minStones
+	}
+	s.bounds[key] = b
+}
+
+// returns the proof for the side to move of a search that winner wins
+func proofFor(winner, toMove engine.Color) exactProof {
+	if winner == toMove {
+		return proofWin
+	}
+	return proofLoss
+}
+
+// reports whether the side to move has reached its goal if the game ended now
+func (s *exactSearch) reached(toMove engine.Color) bool {
+	return (areaScore(s.board) >= s.threshold) == (toMove == engine.Black)
+}
+
+// searches at most depth plies from the current position whether the side to move reaches its goal,
+// passes is the no. of passes just played in a row. results that depend on the line are only kept as move hints
+func (s *exactSearch) search(toMove engine.Color, passes, depth int) exactValue {
+	index := len(s.stones) - 1
+	stones := s.stones[index]
+	if passes >= 2 {
+		proof := proofLoss
+		if s.reached(toMove) {
+			proof = proofWin
+		}
+		return exactValue{proof: proof, dependsOn: math.MaxInt, minStones: stones}
+	}
+	if depth == 0 {
+		return exactValue{proof: proofUnknown, dependsOn: math.MaxInt, minStones: stones}
+	}
+	if s.aborted || s.outOfBudget() {
+		s.aborted = true
+		return exactValue{}
+	}
+	s.nodes++
+
+	posKey, sym := s.key(toMove, passes)
+	if b, ok := s.bounds[posKey]; ok {
+		// bounds proven at other thresholds
+		if b.lower >= s.threshold && s.reusable(b.lowerStones) {
+			return exactValue{proof: proofFor(engine.Black, toMove), dependsOn: math.MaxInt, minStones: b.lowerStones}
+		}
+		if b.upper < s.threshold && s.reusable(b.upperStones) {
+			return exactValue{proof: proofFor(engine.White, toMove), dependsOn: math.MaxInt, minStones: b.upperStones}
+		}
+	}
+	key := s.tableKey(posKey)
+	entry, found := s.table[key]
+	if found && !entry.dependent && entry.depth >= depth && s.reusable(entry.minStones) {
+		return exactValue{proof: proofUnknown, dependsOn: math.MaxInt, minStones: entry.minStones}
+	}
+	hint, hasHint := engine.Point(-1), found && entry.hasBest
+	if hasHint {
+		hint = s.toKeyOrientation(entry.best, sym.Inverse())
+	}
+
+	// a loss rests on every move tried and on the moves refused for repeating the line,
+	// a win only on the winning move. a simple ko ban refuses the position before the last move
+	loss := exactValue{proof: proofLoss, dependsOn: math.MaxInt, minStones: stones}
+	if s.board.KoPoint() >= 0 {
+		loss.dependsOn = index - 1
+	}
+	var result exactValue
+	bestMove, hasBest := engine.Point(-1), false
+	first, tried := engine.Point(-1), false
+	for _, p := range s.orderedMoves(toMove, passes, hint, hasHint) {
+		var child exactValue
+		if p < 0 {
+			s.board.Pass(toMove)
+			child = s.search(opponentColor(toMove), passes+1, depth-1)
+			s.board.Undo()
+		} else {
+			if !s.board.Play(p, toMove) {
+				continue
+			}
+			pathKey := s.pathKey(opponentColor(toMove))
+			if earlier := s.path[pathKey]; earlier > 0 {
+				s.board.Undo()
+				loss.dependsOn = min(loss.dependsOn, earlier-1)
+				continue // superko
+			}
+			s.pushStones(stones + 1 - len(s.board.LastCaptures()))
+			s.path[pathKey] = len(s.stones)
+			child = s.search(opponentColor(toMove), 0, depth-1)
+			delete(s.path, pathKey)
+			s.popStones()
+			s.board.Undo()
+		}
+		if s.aborted {
+			return exactValue{}
+		}
+		if !tried {
+			first, tried = p, true
+		}
+
+		if child.proof == proofLoss {
+			result = exactValue{proof: proofWin, dependsOn: child.dependsOn, minStones: min(stones, child.minStones)}
+			bestMove, hasBest = p, true
+			break
+		}
+		if child.proof == proofUnknown {
+			loss.proof = proofUnknown
+			if !hasBest {
+				bestMove, hasBest = p, true // the most promising move to retry deeper
+			}
+		}
+		loss.dependsOn = min(loss.dependsOn, child.dependsOn)
+		loss.minStones = min(loss.minStones, child.minStones)
+	}
+	if result.proof != proofWin {
+		result = loss
+		if !hasBest {
+			bestMove, hasBest = first, tried // every move loses, the first was thought best
+		}
+	}
+
+	entryDepth := depth
+	if result.proof != proofUnknown {
+		entryDepth = 0
+		if result.dependsOn >= index {
+			s.addBound(posKey, result.proof == proofFor(engine.Black, toMove), result.minStones)
+		}
+	}
+	s.table[key] = exactEntry{
+		depth:     entryDepth,
+		minStones: result.minStones,
+		dependent: result.dependsOn < index,
+		best:      s.toKeyOrientation(bestMove, sym),
+		hasBest:   hasBest,
+	}
+	return result
+}
+
+// returns the moves to search: the table move, then passing if it ends the game in the mover's favour,
+// captures, ataris and saving moves, the rest, passing and last filling own eyes
+func (s *exactSearch) orderedMoves(toMove engine.Color, passes int, hint engine.Point, hasHint bool) []engine.Point {
+	width, height := s.board.Width(), s.board.Height()
+	moves := make([]engine.Point, 0, width*height+1)
+	scores := make([]int, 0, width*height+1)
+	add := func(p engine.Point, score int) {
+		// insertion keeps equal scores in board order
+		i := len(moves)
+		moves, scores = append(moves, p), append(scores, score)
+		for ; i > 0 && scores[i-1] < score; i-- {
+			moves[i], scores[i] = moves[i-1], scores[i-1]
+		}
+		moves[i], scores[i] = p, score
+	}
+
+	enemy := opponentColor(toMove)
+	for y := 1; y <= height; y++ {
+		for x := 1; x <= width; x++ {
+			p := s.board.ToPoint(x, y)
+			if !s.board.IsLegal(p, toMove) {
+				continue
+			}
+			// the root also respects the superko history of the game
+			if s.board.Depth() == 0 && !s.root.IsLegal(p, toMove) {
+				continue
+			}
+
+			score := 1
+			for _, n := range s.board.Neighbors(p) {
+				libs := s.board.Liberties(n)
+				switch c := s.board.ColorAt(n); {
+				case c == enemy && libs == 1:
+					score = max(score, 4)
+				case (c == enemy && libs == 2) || (c == toMove && libs == 1):
+					score = max(score, 3)
+				}
+			}
+			if score == 1 && isOwnEye(s.board, p, toMove) {
+				score = -1
+			}
+			if hasHint && p == hint {
+				score = 6
+			}
+			add(p, score)
+		}
+	}
+
+	passScore := 0
+	if passes > 0 && s.reached(toMove) {
+		passScore = 5
+	}
+	if hasHint && hint < 0 {
+		passScore = 6
+	}
+	add(-1, passScore)
+	return moves
+}
+
+// follows the best moves of the table for threshold t from the root
+func (s *exactSearch) principalVariation(toMove engine.Color, t int) []engine.Move {
+	s.threshold = t
+	var pv []engine.Move
+	passes := 0
+	for passes < 2 && len(pv) <= s.board.Width()*s.board.Height()*3 {
+		key, sym := s.key(toMove, passes)
+		entry, ok := s.table[s.tableKey(key)]
+		if !ok || !entry.hasBest {
+			break
+		}
+		move := engine.Move{Point: s.toKeyOrientation(entry.best, sym.Inverse()), Color: toMove}
+		if move.Point < 0 {
+			s.board.Pass(toMove)
+			passes++
+		} else {
+			if !s.board.Play(move.Point, toMove) {
+				break
+			}
+			passes = 0
+		}
+		pv = append(pv, move)
+		toMove = opponentColor(toMove)
+	}
+	for range pv {
+		s.board.Undo()
+	}
+	return pv
+}
+
+// reports whether p is a one-point eye of color whose groups all have other liberties
+// filling it is rarely good, so it is tried last
+func isOwnEye(board *engine.PlayoutBoard, p engine.Point, color engine.Color) bool {
+	for _, n := range board.Neighbors(p) {
+		switch board.ColorAt(n) {
+		case engine.Border:
+		case color:
+			if board.Liberties(n) < 2 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// returns the no. of stones on the board
+func countStones(board *engine.PlayoutBoard) int {
+	count := 0
+	for y := 1; y <= board.Height(); y++ {
+		for x := 1; x <= board.Width(); x++ {
+			if c := board.ColorAt(board.ToPoint(x, y)); c == engine.Black || c == engine.White {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// returns black's area minus white's, counting every stone as alive
+func areaScore(board *engine.PlayoutBoard) int {
+	width, height := board.Width(), board.Height()
+	var visited [(maxSolverSize + 2) * (maxSolverSize + 2)]bool
+	var buf [maxSolverSize * maxSolverSize]engine.Point
+	score := 0
+	for y := 1; y <= height; y++ {
+		for x := 1; x <= width; x++ {
+			p := board.ToPoint(x, y)
+			switch board.ColorAt(p) {
+			case engine.Black:
+				score++
+			case engine.White:
+				score--
+			case engine.Empty:
+				if visited[p] {
+					continue
+				}
+				// flood fill the empty region and see whose stones it reaches
+				area, reachesBlack, reachesWhite := 0, false, false
+				stack := append(buf[:0], p)
+				visited[p] = true
+				for len(stack) > 0 {
+					cur := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					area++
+					for _, n := range board.Neighbors(cur) {
+						switch board.ColorAt(n) {
+						case engine.Empty:
+							if !visited[n] {
+								visited[n] = true
+								stack = append(stack, n)
+							}
+						case engine.Black:
+							reachesBlack = true
+						case engine.White:
+							reachesWhite = true
+						}
+					}
+				}
+				if reachesBlack && !reachesWhite {
+					score += area
+				} else if reachesWhite && !reachesBlack {
+					score -= area
+				}
+			}
+		}
+	}
+	return score
+}
+
+// SolverBot plays the moves of the exact solver
+type SolverBot struct {
+	Solver   *ExactSolver
+	Fallback Bot // plays when the board is too large or the budget runs out before a bound is proven
+}
+
+// NewSolverBot creates a bot around an exact solver with the given komi, a budget of 2M positions
+// and a heuristic fallback
+func NewSolverBot(komi float64) *SolverBot {
+	solver := NewExactSolver(komi)
+	solver.MaxNodes = 2000000
+	return &SolverBot{Solver: solver, Fallback: NewHeuristicBot()}
+}
+
+// implements the Bot interface, playing the move that proves the best bound found for color
+// even if the budget ran out before the score was exact
+func (bot *SolverBot) SelectMove(board *engine.Board, color engine.Color) engine.Move {
+	result, err := bot.Solver.Solve(board, color)
+	if err == nil && len(result.PV) > 0 {
+		return result.PV[0]
+	}
+	if bot.Fallback == nil {
+		return engine.Move{Point: -1, Color: color}
+	}
+	return bot.Fallback.SelectMove(board, color)
+}
@@ -0,0 +1,229 @@
+package ai
+
+import (
+	"strings"
+
+	"github.com/awesohame/gogo/internal/engine"
+)
+
+// Tactic is the set of tactical categories a move belongs to
+type Tactic uint
+
+const (
+	TacticCapture   Tactic = 1 << iota // removes enemy stones
+	TacticAtari                        // leaves an enemy group with one liberty
+	TacticEscape                       // saves an own group in atari, without running into a lost ladder
+	TacticConnect                      // joins two or more own groups
+	TacticCut                          // takes a liberty shared by two or more enemy groups
+	TacticSelfAtari                    // leaves the played group with one liberty, without capturing
+	TacticLadder                       // ataris a group that cannot escape the ladder
+
+	// TacticNone is the empty set
+	TacticNone Tactic = 0
+)
+
+var tacticNames = []struct {
+	tactic Tactic
+	name   string
+}{
+	{TacticCapture, "capture"},
+	{TacticAtari, "atari"},
+	{TacticEscape, "escape"},
+	{TacticConnect, "connect"},
+	{TacticCut, "cut"},
+	{TacticSelfAtari, "self-atari"},
+	{TacticLadder, "ladder"},
+}
+
+// reports whether all categories of c are in t
+func (t Tactic) Has(c Tactic) bool {
+	return t&c == c
+}
+
+// returns the categories joined with "|", "none" for the empty set
+func (t Tactic) String() string {
+	names := make([]string, 0, len(tacticNames))
+	for _, tn := range tacticNames {
+		if t.Has(tn.tactic) {
+			names = append(names, tn.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// returns the tactical categories of a move, TacticNone for illegal moves
+// everything but ladders is read from the groups around the point without playing the move
+func ClassifyMove(board *engine.Board, move engine.Move) Tactic {
+	p, color := move.Point, move.Color
+	if !board.IsLegal(p, color) {
+		return TacticNone
+	}
+	enemy := opponentColor(color)
+
+	tactic := TacticNone
+	var own, enemies [4]engine.Group
+	ownCount, enemyCount := 0, 0
+	ownInAtari := false
+	for _, n := range board.Neighbors(p) {
+		group, ok := board.GroupAt(n)
+		if !ok {
+			continue
+		}
+		if group.Color == color {
+			if !containsGroup(own[:ownCount], group) {
+				own[ownCount] = group
+				ownCount++
+				ownInAtari = ownInAtari || group.InAtari()
+			}
+		} else if !containsGroup(enemies[:enemyCount], group) {
+			enemies[enemyCount] = group
+			enemyCount++
+		}
+	}
+	if ownCount+enemyCount == 0 {
+		return TacticNone // a lone stone has at least two liberties
+	}
+
+	ataris := 0
+	for _, g := range enemies[:enemyCount] {
+		switch g.LibertyCount() {
+		case 1:
+			tactic |= TacticCapture
+		case 2:
+			ataris++
+		}
+	}
+	if ataris > 0 {
+		tactic |= TacticAtari
+	}
+	if ownCount >= 2 {
+		tactic |= TacticConnect
+	}
+	if enemyCount-countCaptured(enemies[:enemyCount]) >= 2 {
+		tactic |= TacticCut
+	}
+
+	libs := board.LibertiesAfter(p, color)
+	if libs == 1 && !tactic.Has(TacticCapture) {
+		tactic |= TacticSelfAtari
+	}
+
+	// reading needs the position after the move
+	needsLadder := (ownInAtari && libs == 2) || (tactic.Has(TacticAtari) && libs >= 2)
+	if ownInAtari && (libs >= 3 || tactic.Has(TacticCapture)) {
+		tactic |= TacticEscape
+	}
+	if !needsLadder {
+		return tactic
+	}
+
+	// ladders are read on a playout board anyway, so play the move on one instead of copying twice
+	next := engine.NewPlayoutBoard(board)
+	if !next.Play(p, color) {
+		return tactic
+	}
+	if ownInAtari && libs == 2 && !tactic.Has(TacticCapture) && !next.IsLadderCaptured(p, enemy) {
+		tactic |= TacticEscape
+	}
+	if tactic.Has(TacticAtari) && libs >= 2 {
+		for _, g := range enemies[:enemyCount] {
+			stone := g.Stones.First()
+			if g.LibertyCount() == 2 && next.IsLadderCaptured(stone, enemy) {
+				tactic |= TacticLadder
+				break
+			}
+		}
+	}
+	return tactic
+}
+
+// reports whether groups holds a group with the same root
+func containsGroup(groups []engine.Group, g engine.Group) bool {
+	for _, other := range groups {
+		if other.ID == g.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// counts groups with a single liberty, captured by the move being classified
+func countCaptured(groups []engine.Group) int {
+	count := 0
+	for _, g := range groups {
+		if g.InAtari() {
+			count++
+		}
+	}
+	return count
+}
+
+// returns the tactical categories of a move on a playout board, reading in place
+// only captures, ataris, escapes and self-ataris are checked, enough for the playout policy
+func classifyPlayoutMove(board *engine.PlayoutBoard, p engine.Point, color engine.Color) Tactic {
+	if !board.IsLegal(p, color) {
+		return TacticNone
+	}
+
+	ownInAtari := false
+	for _, n := range board.Neighbors(p) {
+		if board.ColorAt(n) == color && board.Liberties(n) == 1 {
+			ownInAtari = true
+		}
+	}
+
+	board.Play(p, color)
+	tactic := TacticNone
+	if len(board.LastCaptures()) > 0 {
+		tactic |= TacticCapture
+	}
+	enemy := opponentColor(color)
+	for _, n := range board.Neighbors(p) {
+		if board.ColorAt(n) == enemy && board.Liberties(n) == 1 {
+			tactic |= TacticAtari
+		}
+	}
+	libs := board.Liberties(p)
+	if libs == 1 && !tactic.Has(TacticCapture) {
+		tactic |= TacticSelfAtari
+	}
+	if ownInAtari && (libs >= 3 || tactic.Has(TacticCapture) || (libs == 2 && !board.IsLadderCaptured(p, enemy))) {
+		tactic |= TacticEscape
+	}
+	board.Undo()
+	return tactic
+}
+
+// returns a tactical answer to the last move for playouts: capture the stones just played
+// when they are in atari, else extend an own group the move put in atari when that escapes
+// false when there is nothing urgent, or last is a pass
+func playoutReply(board *engine.PlayoutBoard, last engine.Point, color engine.Color) (engine.Point, bool) {
+	if last < 0 || board.ColorAt(last) != opponentColor(color) {
+		return -1, false
+	}
+
+	var buf [1]engine.Point
+	if libs := board.LibertyPoints(last, buf[:0]); board.Liberties(last) == 1 && len(libs) == 1 {
+		if board.IsLegal(libs[0], color) {
+			return libs[0], true
+		}
+	}
+
+	for _, n := range board.Neighbors(last) {
+		if board.ColorAt(n) != color || board.Liberties(n) != 1 {
+			continue
+		}
+		libs := board.LibertyPoints(n, buf[:0])
+		if len(libs) != 1 {
+			continue
+		}
+		tactic := classifyPlayoutMove(board, libs[0], color)
+		if tactic.Has(TacticEscape) && !isPlayoutEyeFill(board, libs[0], color) {
+			return libs[0], true
+		}
+	}
+	return -1, false
+}
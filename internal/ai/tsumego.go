@@ -0,0 +1,309 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/awesohame/gogo/internal/engine"
+)
+
+// TsumegoStatus is the fate of the target group of a life-and-death problem
+type TsumegoStatus int
+
+const (
+	TsumegoUnknown TsumegoStatus = iota // the budget ran out first
+	TsumegoLives                        // the group cannot be captured, seki included
+	TsumegoDies                         // the attacker captures the group
+)
+
+func (s TsumegoStatus) String() string {
+	switch s {
+	case TsumegoLives:
+		return "lives"
+	case TsumegoDies:
+		return "dies"
+	default:
+		return "unknown"
+	}
+}
+
+// TsumegoSolver reads life-and-death problems out exactly
+//
+// both sides only play inside the region and may pass. the attacker wins once the target
+// group is captured, the defender wins when two passes in a row leave it on the board.
+// proven positions are kept in a transposition table on Board.Hash, which ignores how a
+// position was reached, so superko fights that depend on the move order may be misread
+type TsumegoSolver struct {
+	MaxNodes  int     // positions to visit before giving up (if 0, no limit)
+	TimeLimit float64 // time limit in sec (if 0, no limit)
+	TreeDepth int     // plies of every losing try listed in the refutation tree
+}
+
+// TsumegoResult holds the solution of a problem
+type TsumegoResult struct {
+	Status  TsumegoStatus  // fate of the target with the side to move playing first
+	Success bool           // whether the side to move reaches its goal: kill as attacker, live as defender
+	KeyMove engine.Move    // winning first move when Success, Point -1 for tenuki
+	Tree    []*TsumegoNode // first moves: the key move when Success, else every try with its refutation
+	Nodes   int            // positions visited
+}
+
+// TsumegoNode is a move of the refutation tree with the replies worth showing
+type TsumegoNode struct {
+	Move    engine.Move // Point -1 for a pass
+	Replies []*TsumegoNode
+}
+
+// NewTsumegoSolver creates a solver with a budget of a million positions
+func NewTsumegoSolver() *TsumegoSolver {
+	return &TsumegoSolver{
+		MaxNodes:  1000000,
+		TimeLimit: 0, // no time lim by default
+		TreeDepth: 4,
+	}
+}
+
+// proven result of a position
+type tsumegoEntry struct {
+	win  bool        // for the side to move
+	best engine.Move // winning move when win
+}
+
+// state of one Solve call
+type tsumegoSearch struct {
+	solver   *TsumegoSolver
+	target   engine.Point
+	defender engine.Color
+	region   []engine.Point
+	table    map[uint64]tsumegoEntry
+	aborted  bool
+	searchBudget
+}
+
+// solves whether the group at target lives with toMove playing first
+// region lists the points either side may play at, nil means every point of the board
+func (s *TsumegoSolver) Solve(board *engine.Board, target engine.Point, region []engine.Point, toMove engine.Color) (TsumegoResult, error) {
+	defender := board.ColorAt(target)
+	if defender != engine.Black && defender != engine.White {
+		return TsumegoResult{}, fmt.Errorf("no stone at %s", board.FormatGTP(target))
+	}
+	if region == nil {
+		region = make([]engine.Point, 0, board.Width()*board.Height())
+		for y := 1; y <= board.Height(); y++ {
+			for x := 1; x <= board.Width(); x++ {
+				region = append(region, board.ToPoint(x, y))
+			}
+		}
+	}
+
+	search := &tsumegoSearch{
+		solver:   s,
+		target:   target,
+		defender: defender,
+		region:   region,
+		table:    make(map[uint64]tsumegoEntry),
+
+		searchBudget: newSearchBudget(s.MaxNodes, s.TimeLimit),
+	}
+
+	win := search.solve(board, toMove, false)
+	result := TsumegoResult{Nodes: search.nodes}
+	if search.aborted {
+		return result, nil
+	}
+
+	result.Success = win
+	if win == (toMove == defender) {
+		result.Status = TsumegoLives
+	} else {
+		result.Status = TsumegoDies
+	}
+	// already settled positions have no entry, any move will do there
+	result.KeyMove = engine.Move{Point: -1, Color: toMove}
+	if entry, ok := search.table[search.key(board, toMove, false)]; ok && win {
+		result.KeyMove = entry.best
+	}
+	result.Tree = search.tree(board, toMove, false, 0)
+	return result, nil
+}
+
+// returns the table key of a position
+func (s *tsumegoSearch) key(board *engine.Board, toMove engine.Color, passed bool) uint64 {
+	key := board.Hash()
+	if toMove == engine.White {
+		key ^= whiteToMoveKey
+	}
+	if passed {
+		key ^= passedKey
+	}
+	return key
+}
+
+// reports whether the target has been captured
+func (s *tsumegoSearch) captured(board *engine.Board) bool {
+	return board.ColorAt(s.target) != s.defender
+}
+
+// reports whether the target has two eyes of one point each, surrounded by the target alone
+// the attacker can never play in either of them, so the group lives unconditionally
+func (s *tsumegoSearch) twoEyes(board *engine.Board) bool {
+	group, ok := board.GroupAt(s.target)
+	if !ok {
+		return false
+	}
+	eyes := 0
+	group.Liberties.ForEach(func(lib engine.Point) {
+		for _, n := range board.Neighbors(lib) {
+			if board.ColorAt(n) != engine.Border && !group.Stones.Has(n) {
+				return
+			}
+		}
+		eyes++
+	})
+	return eyes >= 2
+}
+
+// reports whether the side to move reaches its goal, passed is whether the last move was a pass
+// sets aborted and returns false once the budget is used up
+func (s *tsumegoSearch) solve(board *engine.Board, toMove engine.Color, passed bool) bool {
+	if s.captured(board) {
+		return toMove != s.defender
+	}
+	if s.twoEyes(board) {
+		return toMove == s.defender
+	}
+	if s.aborted || s.outOfBudget() {
+		s.aborted = true
+		return false
+	}
+	s.nodes++
+
+	key := s.key(board, toMove, passed)
+	if entry, ok := s.table[key]; ok {
+		return entry.win
+	}
+
+	for _, move := range s.moves(board, toMove) {
+		var childWin bool
+		if move.Point < 0 {
+			if passed {
+				// two passes in a row, the target is still there
+				if toMove == s.defender {
+					s.table[key] = tsumegoEntry{win: true, best: move}
+					return true
+				}
+				continue
+			}
+			childWin = s.solve(board, opponentColor(toMove), true)
+		} else {
+			next, err := board.ApplyMove(move)
+			if err != nil {
+				continue // superko
+			}
+			childWin = s.solve(next, opponentColor(toMove), false)
+		}
+		if s.aborted {
+			return false
+		}
+		if !childWin {
+			s.table[key] = tsumegoEntry{win: true, best: move}
+			return true
+		}
+	}
+
+	s.table[key] = tsumegoEntry{win: false}
+	return false
+}
+
+// returns the legal region moves ordered by urgency, then a pass
+func (s *tsumegoSearch) moves(board *engine.Board, color engine.Color) []engine.Move {
+	// liberties of the target are the points both sides fight over
+	targetLibs := engine.Bitset{}
+	if group, ok := board.GroupAt(s.target); ok {
+		targetLibs = group.Liberties
+	}
+
+	type scored struct {
+		move  engine.Move
+		score int
+	}
+	candidates := make([]scored, 0, len(s.region))
+	for _, p := range s.region {
+		if !board.IsLegal(p, color) {
+			continue
+		}
+		move := engine.Move{Point: p, Color: color}
+		score := 0
+		if targetLibs.Has(p) {
+			score += 4
+		}
+		tactic := ClassifyMove(board, move)
+		switch {
+		case tactic.Has(TacticCapture):
+			score += 8
+		case tactic.Has(TacticEscape):
+			score += 6
+		case tactic.Has(TacticAtari):
+			score += 3
+		case tactic.Has(TacticSelfAtari):
+			score -= 5
+		}
+		if color == s.defender && IsEyeFillingMove(board, move) {
+			score -= 10
+		}
+		candidates = append(candidates, scored{move, score})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+
+	moves := make([]engine.Move, 0, len(candidates)+1)
+	for _, c := range candidates {
+		moves = append(moves, c.move)
+	}
+	return append(moves, engine.Move{Point: -1, Color: color})
+}
+
+// builds the refutation tree below a solved position: the winning move where the side to move
+// wins, every try but a pass where it loses, each down to TreeDepth plies
+func (s *tsumegoSearch) tree(board *engine.Board, toMove engine.Color, passed bool, depth int) []*TsumegoNode {
+	if s.captured(board) || depth >= s.solver.TreeDepth {
+		return nil
+	}
+
+	key := s.key(board, toMove, passed)
+	entry, ok := s.table[key]
+	if !ok {
+		// proven through a transposition with another history, read it again
+		s.solve(board, toMove, passed)
+		if entry, ok = s.table[key]; !ok {
+			return nil
+		}
+	}
+
+	moves := []engine.Move{entry.best}
+	if !entry.win {
+		if depth+1 >= s.solver.TreeDepth {
+			return nil // no room left to show the refutations
+		}
+		moves = s.moves(board, toMove)
+		if len(moves) > 1 {
+			moves = moves[:len(moves)-1] // passing is never an interesting try
+		}
+	}
+
+	nodes := make([]*TsumegoNode, 0, len(moves))
+	for _, move := range moves {
+		node := &TsumegoNode{Move: move}
+		switch {
+		case move.Point < 0 && !passed:
+			node.Replies = s.tree(board, opponentColor(toMove), true, depth+1)
+		case move.Point >= 0:
+			next, err := board.ApplyMove(move)
+			if err != nil {
+				continue
+			}
+			node.Replies = s.tree(next, opponentColor(toMove), false, depth+1)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
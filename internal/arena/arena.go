@@ -0,0 +1,202 @@
+// Package arena plays matches between two bots and measures their strength difference
+package arena
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/awesohame/gogo/internal/ai"
+	"github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/internal/game"
+)
+
+// Player is one side of a match
+type Player struct {
+	Name string
+	New  func(round int) ai.Bot // creates the bot for a game, bots are never shared between games
+}
+
+// Config holds the settings of a match
+type Config struct {
+	Width       int     // board width, 9 if 0
+	Height      int     // board height, Width if 0
+	Komi        float64 // points added to white's score
+	Rules       game.Rules
+	Games       int // no. of games to play
+	Concurrency int // games played at once, 1 if 0
+	MaxMoves    int // moves before a game is stopped and scored as it stands (if 0, 3 per point)
+}
+
+// GameRecord holds a finished game
+type GameRecord struct {
+	Round   int // index of the game in the match, player A is black in even rounds
+	Black   string
+	White   string
+	Width   int
+	Height  int
+	Komi    float64
+	Rules   game.Rules
+	Moves   []engine.Move // passes have Point -1
+	Winner  engine.Color  // Empty for a draw
+	Margin  float64       // points the winner is ahead by, 0 for a draw or a forfeit
+	Forfeit bool          // the loser played an illegal move
+}
+
+// returns the result in SGF form, like "B+3.5", "W+F" or "0"
+func (r GameRecord) Result() string {
+	var winner string
+	switch r.Winner {
+	case engine.Black:
+		winner = "B"
+	case engine.White:
+		winner = "W"
+	default:
+		return "0"
+	}
+	if r.Forfeit {
+		return winner + "+F"
+	}
+	return fmt.Sprintf("%s+%g", winner, r.Margin)
+}
+
+// plays a match between a and b, alternating colours, with a playing black first
+// report is called after each game, one call at a time, with the record and the stats so far;
+// returning false stops new games from starting, games already being played still finish
+func Run(cfg Config, a, b Player, report func(GameRecord, Stats) bool) (Stats, error) {
+	if cfg.Width <= 0 {
+		cfg.Width = 9
+	}
+	if cfg.Height <= 0 {
+		cfg.Height = cfg.Width
+	}
+	if _, err := newSession(cfg); err != nil {
+		return Stats{}, err
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.MaxMoves <= 0 {
+		cfg.MaxMoves = 3 * cfg.Width * cfg.Height
+	}
+
+	var (
+		mu      sync.Mutex
+		stats   Stats
+		stopped bool
+		wg      sync.WaitGroup
+	)
+	rounds := make(chan int)
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for round := range rounds {
+				mu.Lock()
+				stop := stopped
+				mu.Unlock()
+				if stop {
+					continue // handed out before the report asked to stop
+				}
+
+				record := playGame(cfg, a, b, round)
+
+				mu.Lock()
+				stats.add(record)
+				if report != nil && !report(record, stats) {
+					stopped = true
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for round := 0; round < cfg.Games; round++ {
+		mu.Lock()
+		stop := stopped
+		mu.Unlock()
+		if stop {
+			break
+		}
+		rounds <- round
+	}
+	close(rounds)
+	wg.Wait()
+	return stats, nil
+}
+
+// a bot that also looks at the earlier positions of the game, the most recent first
+type historyBot interface {
+	SelectMoveWithHistory(board *engine.Board, color engine.Color, history []*engine.Board) engine.Move
+}
+
+// plays one game, a takes black in even rounds
+func playGame(cfg Config, a, b Player, round int) GameRecord {
+	black, white := a, b
+	if round%2 == 1 {
+		black, white = b, a
+	}
+	bots := map[engine.Color]ai.Bot{
+		engine.Black: black.New(round),
+		engine.White: white.New(round),
+	}
+
+	record := GameRecord{
+		Round:  round,
+		Black:  black.Name,
+		White:  white.Name,
+		Width:  cfg.Width,
+		Height: cfg.Height,
+		Komi:   cfg.Komi,
+		Rules:  cfg.Rules,
+	}
+	session, err := newSession(cfg)
+	if err != nil {
+		panic(err) // the config was checked by Run
+	}
+
+	for len(record.Moves) < cfg.MaxMoves && !session.IsGameOver() {
+		color := session.CurrentTurn()
+		var move engine.Move
+		if bot, ok := bots[color].(historyBot); ok {
+			move = bot.SelectMoveWithHistory(session.CurrentBoard(), color, session.RecentBoards(len(record.Moves)))
+		} else {
+			move = bots[color].SelectMove(session.CurrentBoard(), color)
+		}
+		move.Color = color
+
+		if move.Point < 0 {
+			move.Point = -1
+			session.Pass()
+		} else if err := session.MakeMove(move); err != nil {
+			record.Winner = opponentColor(color)
+			record.Forfeit = true
+			return record
+		}
+		record.Moves = append(record.Moves, move)
+	}
+
+	blackScore, whiteScore, winner := session.GetFinalScore()
+	record.Winner = winner
+	if winner != engine.Empty {
+		record.Margin = max(blackScore-whiteScore, whiteScore-blackScore)
+	}
+	return record
+}
+
+// creates the session of a game
+func newSession(cfg Config) (*game.Session, error) {
+	return game.NewSessionWithConfig(game.Config{
+		Width:  cfg.Width,
+		Height: cfg.Height,
+		Komi:   cfg.Komi,
+		Rules:  cfg.Rules,
+	})
+}
+
+// returns the opp color
+func opponentColor(c engine.Color) engine.Color {
+	if c == engine.Black {
+		return engine.White
+	}
+	return engine.Black
+}
@@ -0,0 +1,57 @@
+package arena
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/internal/game"
+)
+
+// writes a game as an SGF file (FF[4]) with the players, komi, rules and result
+func WriteSGF(w io.Writer, r GameRecord) error {
+	var sb strings.Builder
+	sb.WriteString("(;GM[1]FF[4]CA[UTF-8]AP[gogo]")
+	if r.Width == r.Height {
+		fmt.Fprintf(&sb, "SZ[%d]", r.Width)
+	} else {
+		fmt.Fprintf(&sb, "SZ[%d:%d]", r.Width, r.Height)
+	}
+	fmt.Fprintf(&sb, "KM[%g]RU[%s]", r.Komi, sgfRules(r.Rules))
+	fmt.Fprintf(&sb, "PB[%s]PW[%s]RE[%s]", sgfEscape(r.Black), sgfEscape(r.White), r.Result())
+	fmt.Fprintf(&sb, "GN[round %d]\n", r.Round+1)
+
+	board := engine.NewRectBoard(r.Width, r.Height) // only converts points
+	for i, m := range r.Moves {
+		color := "B"
+		if m.Color == engine.White {
+			color = "W"
+		}
+		point := ""
+		if m.Point >= 0 {
+			point = engine.FormatSGF(board.ToXY(m.Point))
+		}
+		fmt.Fprintf(&sb, ";%s[%s]", color, point)
+		if (i+1)%16 == 0 {
+			sb.WriteByte('\n')
+		}
+	}
+	sb.WriteString(")\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// returns the SGF name of the rules
+func sgfRules(r game.Rules) string {
+	if r == game.TrompTaylorRules {
+		return "Tromp-Taylor"
+	}
+	return "Chinese"
+}
+
+// escapes the characters SGF gives a meaning inside property values
+func sgfEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `]`, `\]`).Replace(s)
+}
@@ -0,0 +1,206 @@
+package arena
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/awesohame/gogo/internal/ai"
+	"github.com/awesohame/gogo/internal/nn"
+)
+
+// parses a bot description like "mcts:sims=400,c=1.2" into a player named after it
+//
+//	mcts       sims (800), time, c (1.414), reuse (true)
+//	alphabeta  depth (3), nodes (200000), time, eval (hybrid)
+//	puct       net (weight file, required), sims (800), time, cpuct (1.5), blend (1)
+//	heuristic  eval (hybrid)
+//	greedy
+//	random     seed (1), each game adds its round
+//
+// every kind takes name to replace the spec as the player's name. moveTime is the time limit
+// per move in sec for mcts, puct and alphabeta when the spec sets none (if 0, none), komi is
+// the komi of the match, which puct scores its playouts and finished lines with
+func ParsePlayer(spec string, moveTime, komi float64) (Player, error) {
+	kind, params, err := parseSpec(spec)
+	if err != nil {
+		return Player{}, err
+	}
+	player := Player{Name: spec}
+	if name, ok := params["name"]; ok {
+		player.Name = name
+		delete(params, "name")
+	}
+	p := specParams{spec: spec, values: params}
+
+	switch kind {
+	case "mcts":
+		sims := p.int("sims", 800)
+		timeLimit := p.float("time", moveTime)
+		c := p.float("c", ai.NewMCTSBot(0).ExplorationC)
+		reuse := p.bool("reuse", true)
+		player.New = func(int) ai.Bot {
+			bot := ai.NewMCTSBot(sims)
+			bot.TimeLimit = timeLimit
+			bot.ExplorationC = c
+			bot.ReuseTree = reuse
+			bot.Verbose = false
+			return bot
+		}
+	case "puct":
+		path, ok := p.take("net")
+		if !ok {
+			return Player{}, fmt.Errorf("puct needs net=<weight file> in %q", spec)
+		}
+		net, err := nn.LoadFile(path)
+		if err != nil {
+			return Player{}, err
+		}
+		sims := p.int("sims", 800)
+		timeLimit := p.float("time", moveTime)
+		cPuct := p.float("cpuct", ai.NewPUCTBot(nil, 0).CPuct)
+		blend := p.float("blend", 1)
+		player.New = func(int) ai.Bot {
+			bot := ai.NewPUCTBot(net, sims) // the network is shared, it is safe for concurrent use
+			bot.TimeLimit = timeLimit
+			bot.CPuct = cPuct
+			bot.ValueWeight = blend
+			bot.Komi = komi
+			return bot
+		}
+	case "alphabeta":
+		depth := p.int("depth", 3)
+		nodes := p.int("nodes", ai.NewAlphaBetaBot(nil).MaxNodes)
+		timeLimit := p.float("time", moveTime)
+		newEvaluator := p.evaluator("eval")
+		player.New = func(int) ai.Bot {
+			bot := ai.NewAlphaBetaBot(newEvaluator())
+			bot.MaxDepth = depth
+			bot.MaxNodes = nodes
+			bot.TimeLimit = timeLimit
+			return bot
+		}
+	case "heuristic":
+		newEvaluator := p.evaluator("eval")
+		player.New = func(int) ai.Bot {
+			return &ai.HeuristicBot{Evaluator: newEvaluator()}
+		}
+	case "greedy":
+		player.New = func(int) ai.Bot {
+			return ai.NewGreedyBot()
+		}
+	case "random":
+		seed := p.int("seed", 1)
+		player.New = func(round int) ai.Bot {
+			return ai.NewRandomBot(int64(seed + round))
+		}
+	default:
+		return Player{}, fmt.Errorf("unknown bot %q in %q", kind, spec)
+	}
+
+	if p.err != nil {
+		return Player{}, p.err
+	}
+	for key := range p.values {
+		return Player{}, fmt.Errorf("unknown option %q for %s in %q", key, kind, spec)
+	}
+	return player, nil
+}
+
+// splits a spec into its kind and key=value options
+func parseSpec(spec string) (string, map[string]string, error) {
+	kind, options, _ := strings.Cut(strings.TrimSpace(spec), ":")
+	params := make(map[string]string)
+	if options == "" {
+		return kind, params, nil
+	}
+	for _, option := range strings.Split(options, ",") {
+		key, value, ok := strings.Cut(option, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return "", nil, fmt.Errorf("option %q in %q is not key=value", option, spec)
+		}
+		params[key] = strings.TrimSpace(value)
+	}
+	return kind, params, nil
+}
+
+// reads the options of a spec, each one read is removed so unknown ones are left over
+// the first bad value is kept in err
+type specParams struct {
+	spec   string
+	values map[string]string
+	err    error
+}
+
+// removes and returns the raw value of key
+func (p *specParams) take(key string) (string, bool) {
+	value, ok := p.values[key]
+	delete(p.values, key)
+	return value, ok
+}
+
+// records a bad value unless an earlier one was found
+func (p *specParams) fail(key, value, want string) {
+	if p.err == nil {
+		p.err = fmt.Errorf("option %s=%q in %q is not %s", key, value, p.spec, want)
+	}
+}
+
+func (p *specParams) int(key string, def int) int {
+	value, ok := p.take(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		p.fail(key, value, "an integer")
+		return def
+	}
+	return n
+}
+
+func (p *specParams) float(key string, def float64) float64 {
+	value, ok := p.take(key)
+	if !ok {
+		return def
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		p.fail(key, value, "a number")
+		return def
+	}
+	return f
+}
+
+func (p *specParams) bool(key string, def bool) bool {
+	value, ok := p.take(key)
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		p.fail(key, value, "true or false")
+		return def
+	}
+	return b
+}
+
+// returns a constructor of the evaluator named by key, hybrid by default
+func (p *specParams) evaluator(key string) func() ai.Evaluator {
+	value, ok := p.take(key)
+	if !ok {
+		value = "hybrid"
+	}
+	switch value {
+	case "simple":
+		return func() ai.Evaluator { return &ai.SimpleEvaluator{} }
+	case "influence":
+		return func() ai.Evaluator { return &ai.InfluenceEvaluator{} }
+	case "hybrid":
+		return func() ai.Evaluator { return ai.NewHybridEvaluator() }
+	default:
+		p.fail(key, value, "simple, influence or hybrid")
+		return nil
+	}
+}
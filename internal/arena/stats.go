@@ -0,0 +1,156 @@
+package arena
+
+import (
+	"math"
+
+	"github.com/awesohame/gogo/internal/engine"
+)
+
+// Stats counts the results of a match from player A's side
+type Stats struct {
+	Games    int
+	Wins     int
+	Losses   int
+	Draws    int
+	Forfeits int // games lost to an illegal move, by either player
+}
+
+// 95% two sided normal quantile
+const z95 = 1.959964
+
+// counts a finished game, A is black in even rounds
+func (s *Stats) add(r GameRecord) {
+	s.Games++
+	if r.Forfeit {
+		s.Forfeits++
+	}
+	aColor := engine.Black
+	if r.Round%2 == 1 {
+		aColor = engine.White
+	}
+	switch r.Winner {
+	case engine.Empty:
+		s.Draws++
+	case aColor:
+		s.Wins++
+	default:
+		s.Losses++
+	}
+}
+
+// returns A's average points per game, a win is 1 and a draw 1/2
+func (s Stats) Score() float64 {
+	if s.Games == 0 {
+		return 0.5
+	}
+	return (float64(s.Wins) + 0.5*float64(s.Draws)) / float64(s.Games)
+}
+
+// returns the variance of a single game's points around the mean score
+func (s Stats) variance() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+	mean := s.Score()
+	n := float64(s.Games)
+	return (float64(s.Wins)*(1-mean)*(1-mean) +
+		float64(s.Draws)*(0.5-mean)*(0.5-mean) +
+		float64(s.Losses)*mean*mean) / n
+}
+
+// returns the 95% confidence interval of the score, clamped to [0, 1]
+func (s Stats) ScoreInterval() (lo, hi float64) {
+	if s.Games == 0 {
+		return 0, 1
+	}
+	margin := z95 * math.Sqrt(s.variance()/float64(s.Games))
+	return math.Max(0, s.Score()-margin), math.Min(1, s.Score()+margin)
+}
+
+// returns A's Elo advantage over B with its 95% confidence interval
+// a perfect score gives +Inf, a zero score -Inf
+func (s Stats) Elo() (elo, lo, hi float64) {
+	scoreLo, scoreHi := s.ScoreInterval()
+	return ScoreToElo(s.Score()), ScoreToElo(scoreLo), ScoreToElo(scoreHi)
+}
+
+// converts an expected score to an Elo difference with the logistic model
+func ScoreToElo(score float64) float64 {
+	if score <= 0 {
+		return math.Inf(-1)
+	}
+	if score >= 1 {
+		return math.Inf(1)
+	}
+	return -400 * math.Log10(1/score-1)
+}
+
+// converts an Elo difference to the expected score of the stronger side
+func EloToScore(elo float64) float64 {
+	return 1 / (1 + math.Pow(10, -elo/400))
+}
+
+// Verdict is the outcome of a sequential probability ratio test
+type Verdict int
+
+const (
+	Continue Verdict = iota // not enough games to decide
+	AcceptH0                // A is no more than Elo0 stronger
+	AcceptH1                // A is at least Elo1 stronger
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case AcceptH0:
+		return "H0 accepted"
+	case AcceptH1:
+		return "H1 accepted"
+	default:
+		return "continue"
+	}
+}
+
+// SPRT tests whether A is Elo0 (H0) or Elo1 (H1) stronger than B
+//
+// the log-likelihood ratio uses the normal approximation of the game results
+// (generalized SPRT), which also handles draws
+type SPRT struct {
+	Elo0  float64
+	Elo1  float64
+	Alpha float64 // chance of accepting H1 when H0 holds
+	Beta  float64 // chance of accepting H0 when H1 holds
+}
+
+// NewSPRT creates a test of elo0 against elo1 with 5% error rates
+func NewSPRT(elo0, elo1 float64) SPRT {
+	return SPRT{Elo0: elo0, Elo1: elo1, Alpha: 0.05, Beta: 0.05}
+}
+
+// returns the log-likelihood ratio of H1 against H0 for the results so far
+func (t SPRT) LLR(s Stats) float64 {
+	variance := s.variance()
+	if s.Games == 0 || variance == 0 {
+		return 0 // one kind of result only, nothing to go on yet
+	}
+	s0, s1 := EloToScore(t.Elo0), EloToScore(t.Elo1)
+	return float64(s.Games) * (s1 - s0) * (2*s.Score() - s0 - s1) / (2 * variance)
+}
+
+// returns the LLR below which H0 and above which H1 is accepted
+func (t SPRT) Bounds() (lower, upper float64) {
+	return math.Log(t.Beta / (1 - t.Alpha)), math.Log((1 - t.Beta) / t.Alpha)
+}
+
+// returns the decision for the results so far
+func (t SPRT) Verdict(s Stats) Verdict {
+	llr := t.LLR(s)
+	lower, upper := t.Bounds()
+	switch {
+	case llr >= upper:
+		return AcceptH1
+	case llr <= lower:
+		return AcceptH0
+	default:
+		return Continue
+	}
+}
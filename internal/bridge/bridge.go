@@ -0,0 +1,8 @@
+// Package bridge lets the public packages reach each other's internals
+// without exporting internal types in their APIs
+package bridge
+
+import "github.com/awesohame/gogo/internal/game"
+
+// GameSession returns the session behind a *pkg/engine.Game, set by pkg/engine
+var GameSession func(g any) *game.Session
@@ -0,0 +1,152 @@
+package engine
+
+import "math/bits"
+
+// no. of points on the largest padded grid (board plus border)
+const maxPoints = (MaxBoardSize + 2) * (MaxBoardSize + 2)
+
+const bitsetWords = (maxPoints + 63) / 64
+
+// Bitset is a fixed-width set of points over the padded board grid
+// it is a plain value, so copying a board or group copies its sets without allocating
+type Bitset [bitsetWords]uint64
+
+// adds a point to the set
+func (s *Bitset) Set(p Point) {
+	s[p>>6] |= 1 << (uint(p) & 63)
+}
+
+// removes a point from the set
+func (s *Bitset) Clear(p Point) {
+	s[p>>6] &^= 1 << (uint(p) & 63)
+}
+
+// returns whether a point is in the set
+func (s *Bitset) Has(p Point) bool {
+	return s[p>>6]&(1<<(uint(p)&63)) != 0
+}
+
+// returns the no. of points in the set
+func (s *Bitset) Count() int {
+	n := 0
+	for _, w := range s {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// returns whether the set has no points
+func (s *Bitset) IsEmpty() bool {
+	for _, w := range s {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// returns the lowest point in the set, or -1 if empty
+func (s *Bitset) First() Point {
+	for i, w := range s {
+		if w != 0 {
+			return Point(i*64 + bits.TrailingZeros64(w))
+		}
+	}
+	return -1
+}
+
+// returns the points in the set in ascending order
+func (s *Bitset) Points() []Point {
+	points := make([]Point, 0, s.Count())
+	s.ForEach(func(p Point) {
+		points = append(points, p)
+	})
+	return points
+}
+
+// calls fn for every point in the set in ascending order
+func (s *Bitset) ForEach(fn func(p Point)) {
+	for i, w := range s {
+		for w != 0 {
+			fn(Point(i*64 + bits.TrailingZeros64(w)))
+			w &= w - 1
+		}
+	}
+}
+
+// returns the union of two sets
+func (s Bitset) Or(o Bitset) Bitset {
+	for i := range s {
+		s[i] |= o[i]
+	}
+	return s
+}
+
+// returns the intersection of two sets
+func (s Bitset) And(o Bitset) Bitset {
+	for i := range s {
+		s[i] &= o[i]
+	}
+	return s
+}
+
+// returns the points of s that are not in o
+func (s Bitset) AndNot(o Bitset) Bitset {
+	for i := range s {
+		s[i] &^= o[i]
+	}
+	return s
+}
+
+// returns whether the two sets share any point
+func (s *Bitset) Intersects(o *Bitset) bool {
+	for i := range s {
+		if s[i]&o[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the set moved k points up the grid (bit i goes to i+k)
+func (s Bitset) shiftUp(k uint) Bitset {
+	var r Bitset
+	for i := len(s) - 1; i >= 0; i-- {
+		r[i] = s[i] << k
+		if i > 0 {
+			r[i] |= s[i-1] >> (64 - k)
+		}
+	}
+	return r
+}
+
+// returns the set moved k points down the grid (bit i goes to i-k)
+func (s Bitset) shiftDown(k uint) Bitset {
+	var r Bitset
+	for i := range s {
+		r[i] = s[i] >> k
+		if i < len(s)-1 {
+			r[i] |= s[i+1] << (64 - k)
+		}
+	}
+	return r
+}
+
+// returns the points orthogonally adjacent to the set, on a grid with the given row stride
+// the set itself is not included unless its points neighbor each other
+func (s Bitset) neighbors(stride int) Bitset {
+	k := uint(stride)
+	return s.shiftUp(1).Or(s.shiftDown(1)).Or(s.shiftUp(k)).Or(s.shiftDown(k))
+}
+
+// grows a region inside mask until it stops changing, returns the connected area
+func (s Bitset) floodFill(mask Bitset, stride int) Bitset {
+	region := s.And(mask)
+	for {
+		next := region.Or(region.neighbors(stride).And(mask))
+		if next == region {
+			return region
+		}
+		region = next
+	}
+}
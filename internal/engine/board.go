@@ -1,10 +1,16 @@
 package engine
 
 import (
-	"errors"
+	"fmt"
 	"strings"
 )
 
+// supported board dimensions, boards may be rectangular
+const (
+	MinBoardSize = 2
+	MaxBoardSize = 25
+)
+
 // Color is the state of a point
 type Color int8
 
@@ -21,24 +27,39 @@ type Point int16
 // Board represents game state
 type Board struct {
 	points       []Color
-	size         int
-	internalSize int
-	koPoint      Point    // active Ko point
-	koHash       uint64   // Zobrist hash for Ko detection
-	history      []uint64 // Zobrist hash history for superko
-	groups       map[Point]*Group
-	dsu          *DSU
-	nextGroupID  int
+	width        int
+	height       int
+	internalSize int             // row stride of the padded grid (width + 2)
+	koPoint      Point           // active Ko point
+	koHash       uint64          // Zobrist hash for Ko detection
+	history      positionHistory // Zobrist hash history for superko, shared between boards
+	black        Bitset          // black stones
+	white        Bitset          // white stones
+	onBoard      Bitset          // all playable points
+	groups       []*Group        // group of the stone at each point, nil if empty
 }
 
-// NewBoard inits new board of a given size
+// NewBoard inits new square board of a given size
 func NewBoard(size int) *Board {
-	if size <= 0 {
-		size = 9 // default board size
+	return NewRectBoard(size, size)
+}
+
+// NewRectBoard inits a new board that is width columns wide and height rows tall
+// non-positive dimensions default to 9, other sizes outside MinBoardSize..MaxBoardSize panic
+func NewRectBoard(width, height int) *Board {
+	if width <= 0 {
+		width = 9 // default board size
+	}
+	if height <= 0 {
+		height = width
+	}
+	if err := ValidateSize(width, height); err != nil {
+		panic(err)
 	}
 
-	internalSize := size + 2
-	points := make([]Color, internalSize*internalSize)
+	internalSize := width + 2
+	internalHeight := height + 2
+	points := make([]Color, internalSize*internalHeight)
 
 	// init all points to Empty and set borders
 	for i := range points {
@@ -48,22 +69,29 @@ func NewBoard(size int) *Board {
 	// set top and bottom borders
 	for i := 0; i < internalSize; i++ {
 		points[i] = Border
-		points[i+internalSize*(internalSize-1)] = Border
+		points[i+internalSize*(internalHeight-1)] = Border
 	}
 
 	// set left and right borders
-	for i := 0; i < internalSize; i++ {
+	for i := 0; i < internalHeight; i++ {
 		points[i*internalSize] = Border
 		points[i*internalSize+internalSize-1] = Border
 	}
 
+	var onBoard Bitset
+	for i, c := range points {
+		if c == Empty {
+			onBoard.Set(Point(i))
+		}
+	}
+
 	return &Board{
 		points:       points,
-		size:         size,
+		width:        width,
+		height:       height,
 		internalSize: internalSize,
-		groups:       make(map[Point]*Group),
-		dsu:          NewDSU(size * size * 100), // extra room for MCTS
-		history:      make([]uint64, 0),
+		onBoard:      onBoard,
+		groups:       make([]*Group, len(points)),
 		koPoint:      -1, // use -1 for no active Ko point
 	}
 }
@@ -73,48 +101,107 @@ func (b *Board) copy() *Board {
 	newPoints := make([]Color, len(b.points))
 	copy(newPoints, b.points)
 
-	// check if all groups are proper
-	newGroups := make(map[Point]*Group, len(b.groups))
-	groupCopies := make(map[int]*Group) // map from group ID to copied group
-
-	for k, v := range b.groups {
-		if v != nil {
-			// check if group is already copied
-			if copiedGroup, exists := groupCopies[v.ID]; exists {
-				newGroups[k] = copiedGroup
-			} else {
-				// no copy exists, make copy
-				copiedGroup := v.copy()
-				groupCopies[v.ID] = copiedGroup
-				newGroups[k] = copiedGroup
-			}
+	// copy each group once, when reaching its lowest stone
+	newGroups := make([]*Group, len(b.groups))
+	for i, g := range b.groups {
+		if g == nil || g.Stones.First() != Point(i) {
+			continue
 		}
+		copiedGroup := g.copy()
+		g.Stones.ForEach(func(stone Point) {
+			newGroups[stone] = copiedGroup
+		})
 	}
 
-	newHistory := make([]uint64, len(b.history))
-	copy(newHistory, b.history)
-
 	return &Board{
 		points:       newPoints,
-		size:         b.size,
+		width:        b.width,
+		height:       b.height,
 		internalSize: b.internalSize,
+		black:        b.black,
+		white:        b.white,
+		onBoard:      b.onBoard,
 		groups:       newGroups,
-		dsu:          b.dsu.copy(),
-		history:      newHistory,
+		history:      b.history,
 		koPoint:      b.koPoint,
 		koHash:       b.koHash,
-		nextGroupID:  b.nextGroupID,
 	}
 }
 
+// puts a stone on an empty point, keeping stone sets and hash in sync
+func (b *Board) placeStone(p Point, c Color) {
+	b.points[p] = c
+	if c == Black {
+		b.black.Set(p)
+	} else {
+		b.white.Set(p)
+	}
+	b.koHash ^= zobristKey(p, c)
+}
+
+// lifts a stone off the board, keeping stone sets and hash in sync
+func (b *Board) removeStone(p Point) {
+	b.koHash ^= zobristKey(p, b.points[p])
+	b.points[p] = Empty
+	b.black.Clear(p)
+	b.white.Clear(p)
+}
+
+// returns the set of empty points
+func (b *Board) emptyPoints() Bitset {
+	return b.onBoard.AndNot(b.black.Or(b.white))
+}
+
+// parses a board size like "19" or "19x13" (width x height)
+func ParseBoardSize(s string) (width int, height int, err error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	w, h, rect := strings.Cut(s, "x")
+	if _, err := fmt.Sscan(w, &width); err != nil {
+		return 0, 0, fmt.Errorf("invalid board size %q", s)
+	}
+	height = width
+	if rect {
+		if _, err := fmt.Sscan(h, &height); err != nil {
+			return 0, 0, fmt.Errorf("invalid board size %q", s)
+		}
+	}
+	if err := ValidateSize(width, height); err != nil {
+		return 0, 0, err
+	}
+	return width, height, nil
+}
+
 // convert 1-based (x, y) coords to a Point
 func (b *Board) ToPoint(x int, y int) Point {
 	return Point(y*b.internalSize + x)
 }
 
-// returns board size (9, 13, 19, etc)
+// returns board size (9, 13, 19, etc), the width on rectangular boards
 func (b *Board) Size() int {
-	return b.size
+	return b.width
+}
+
+// returns no. of columns
+func (b *Board) Width() int {
+	return b.width
+}
+
+// returns no. of rows
+func (b *Board) Height() int {
+	return b.height
+}
+
+// checks whether board dimensions are supported
+func ValidateSize(width, height int) error {
+	if width < MinBoardSize || width > MaxBoardSize || height < MinBoardSize || height > MaxBoardSize {
+		return fmt.Errorf("board size %dx%d out of range (%d to %d)", width, height, MinBoardSize, MaxBoardSize)
+	}
+	return nil
+}
+
+// returns the point the last move made a ko at, -1 if none
+func (b *Board) KoPoint() Point {
+	return b.koPoint
 }
 
 // returns zobrist hash of curr board
@@ -150,10 +237,18 @@ func (b *Board) Neighbors(p Point) [4]Point {
 }
 
 // returns a string representation of the board for display
+// with GTP column letters on top and row numbers counting up from the bottom
 func (b *Board) String() string {
 	var sb strings.Builder
-	for y := 1; y <= b.size; y++ {
-		for x := 1; x <= b.size; x++ {
+	sb.WriteString("   ")
+	for x := 1; x <= b.width; x++ {
+		sb.WriteByte(gtpColumns[x-1])
+		sb.WriteByte(' ')
+	}
+	sb.WriteString("\n")
+	for y := 1; y <= b.height; y++ {
+		fmt.Fprintf(&sb, "%2d ", b.height-y+1)
+		for x := 1; x <= b.width; x++ {
 			p := b.ToPoint(x, y)
 			switch b.points[p] {
 			case Empty:
@@ -171,16 +266,30 @@ func (b *Board) String() string {
 
 // validates and applies a move, returning a NEW board state
 func (b *Board) ApplyMove(move Move) (*Board, error) {
+	newBoard, _, err := b.ApplyMoveWithResult(move)
+	return newBoard, err
+}
+
+// MoveResult describes what a move did to the board
+type MoveResult struct {
+	Captured  []Point // stones removed from the board, nil if none
+	KoPoint   Point   // point the opponent may not retake at immediately, -1 if none
+	Liberties int     // liberties of the group containing the placed stone
+}
+
+// validates and applies a move, returning a NEW board state and what the move did
+func (b *Board) ApplyMoveWithResult(move Move) (*Board, MoveResult, error) {
 	// validate move
 	if err := b.validatePlacement(move); err != nil {
-		return nil, err
+		return nil, MoveResult{}, err
 	}
 
 	// create a new board state by copying the current one
 	newBoard := b.copy()
+	newBoard.koPoint = -1
 
 	// place stone
-	newBoard.points[move.Point] = move.Color
+	newBoard.placeStone(move.Point, move.Color)
 
 	// create a new group for the placed stone
 	newGroup := newBoard.createNewGroup(move.Point, move.Color)
@@ -192,22 +301,32 @@ func (b *Board) ApplyMove(move Move) (*Board, error) {
 	newBoard.updateEnemyLiberties(move.Point, move.Color)
 
 	// resolve captures for any enemy groups now at 0 liberties
-	capturedStones := newBoard.resolveCaptures(move.Point, move.Color)
-	_ = capturedStones // probably will use later for Ko logic
+	captured := newBoard.resolveCaptures(move.Point, move.Color)
 
 	// check for suicide (after captures are resolved)
 	if err := newBoard.validateSuicide(move.Point); err != nil {
-		return nil, err
+		return nil, MoveResult{}, err
 	}
 
-	// update board hash and check for Ko
-	newBoard.koHash = newBoard.computeHash()
-	if newBoard.isPositionRepeated() {
-		return nil, errors.New("illegal move: Ko rule violation")
+	// hash was updated incrementally, check for Ko
+	if moveNumber, repeated := newBoard.isPositionRepeated(); repeated {
+		return nil, MoveResult{}, &KoError{MoveNumber: moveNumber}
 	}
 
 	// add current hash to history
-	newBoard.history = append(newBoard.history, newBoard.koHash)
+	newBoard.history = newBoard.history.extend(newBoard.koHash)
+
+	group := newBoard.groups[move.Point]
+	result := MoveResult{KoPoint: -1, Liberties: group.Liberties.Count()}
+	if !captured.IsEmpty() {
+		result.Captured = captured.Points()
+	}
+
+	// a single stone capturing a single stone and left in atari at that point is a ko
+	if len(result.Captured) == 1 && group.Stones.Count() == 1 && result.Liberties == 1 {
+		newBoard.koPoint = result.Captured[0]
+		result.KoPoint = newBoard.koPoint
+	}
 
-	return newBoard, nil
+	return newBoard, result, nil
 }
@@ -1,27 +1,22 @@
 package engine
 
 // checks all enemy neighbors and removes groups with zero liberties
-// returns count of captured stones
-func (b *Board) resolveCaptures(p Point, placedColor Color) int {
-	capturedCount := 0
-	capturedGroups := make(map[int]bool) // track already processed groups
+// returns the captured stones
+func (b *Board) resolveCaptures(p Point, placedColor Color) Bitset {
+	var captured Bitset
 
 	for _, n := range b.Neighbors(p) {
 		if b.points[n] != Empty && b.points[n] != placedColor {
-			// this is an enemy neighbor
+			// this is an enemy neighbor, a captured group is gone from the map already
 			enemyGroup := b.groups[n]
-			if enemyGroup != nil && !capturedGroups[enemyGroup.ID] {
-				// check if this group has zero liberties
-				if len(enemyGroup.Liberties) == 0 {
-					// capture this group
-					capturedCount += b.captureGroup(enemyGroup)
-					capturedGroups[enemyGroup.ID] = true
-				}
+			if enemyGroup != nil && enemyGroup.Liberties.IsEmpty() {
+				captured = captured.Or(enemyGroup.Stones)
+				b.captureGroup(enemyGroup)
 			}
 		}
 	}
 
-	return capturedCount
+	return captured
 }
 
 // removes all stones of a group from the board and updates liberties of adjacent groups
@@ -30,26 +25,25 @@ func (b *Board) captureGroup(group *Group) int {
 	capturedCount := 0
 
 	// for each stone in the captured group
-	for stone := range group.Stones {
+	group.Stones.ForEach(func(stone Point) {
 		// remove the stone from the board
-		b.points[stone] = Empty
+		b.removeStone(stone)
 
 		// remove from groups map
-		delete(b.groups, stone)
+		b.groups[stone] = nil
 
 		capturedCount++
+	})
 
-		// update liberties of adjacent groups (they now have a new liberty)
+	// update liberties of adjacent groups (they now have a new liberty)
+	group.Stones.ForEach(func(stone Point) {
 		for _, n := range b.Neighbors(stone) {
-			if b.points[n] != Empty {
-				neighborGroup := b.groups[n]
-				if neighborGroup != nil {
-					// this adjacent group gains the newly empty point as a liberty
-					neighborGroup.Liberties[stone] = struct{}{}
-				}
+			if neighborGroup := b.groups[n]; neighborGroup != nil {
+				// this adjacent group gains the newly empty point as a liberty
+				neighborGroup.Liberties.Set(stone)
 			}
 		}
-	}
+	})
 
 	return capturedCount
 }
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GTP column letters, I is skipped to avoid confusion with J
+const gtpColumns = "ABCDEFGHJKLMNOPQRSTUVWXYZ"
+
+// formats 1-based (x, y) coords as GTP vertex like "D4"
+// columns are letters from the left, rows count up from the bottom
+func FormatGTP(x, y, height int) string {
+	if x < 1 || x > len(gtpColumns) {
+		return fmt.Sprintf("%d-%d", x, height-y+1)
+	}
+	return fmt.Sprintf("%c%d", gtpColumns[x-1], height-y+1)
+}
+
+// parses a GTP vertex like "D4" or "d4" into 1-based (x, y) coords
+func ParseGTP(s string, width, height int) (int, int, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) < 2 {
+		return 0, 0, fmt.Errorf("invalid coordinate %q", s)
+	}
+	x := strings.IndexByte(gtpColumns, s[0]) + 1
+	row, err := strconv.Atoi(s[1:])
+	if x == 0 || err != nil {
+		return 0, 0, fmt.Errorf("invalid coordinate %q", s)
+	}
+	y := height - row + 1
+	if x > width || y < 1 || y > height {
+		return 0, 0, fmt.Errorf("%w: %s", ErrOffBoard, s)
+	}
+	return x, y, nil
+}
+
+// formats 1-based (x, y) coords as SGF point like "dd", both letters count from the top left
+func FormatSGF(x, y int) string {
+	return string([]byte{byte('a' + x - 1), byte('a' + y - 1)})
+}
+
+// parses an SGF point like "dd" into 1-based (x, y) coords
+func ParseSGF(s string, width, height int) (int, int, error) {
+	s = strings.TrimSpace(s)
+	if len(s) != 2 || s[0] < 'a' || s[0] > 'z' || s[1] < 'a' || s[1] > 'z' {
+		return 0, 0, fmt.Errorf("invalid coordinate %q", s)
+	}
+	x, y := int(s[0]-'a')+1, int(s[1]-'a')+1
+	if x > width || y > height {
+		return 0, 0, fmt.Errorf("%w: %s", ErrOffBoard, s)
+	}
+	return x, y, nil
+}
+
+// parses coords in any supported form into 1-based (x, y) coords:
+// GTP "D4", SGF "dd" or numeric "4 6" / "4,6" (x from the left, y from the top)
+func ParseCoord(s string, width, height int) (int, int, error) {
+	s = strings.TrimSpace(s)
+	if fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' }); len(fields) == 2 {
+		x, err1 := strconv.Atoi(fields[0])
+		y, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil {
+			return 0, 0, fmt.Errorf("invalid coordinate %q", s)
+		}
+		if x < 1 || x > width || y < 1 || y > height {
+			return 0, 0, fmt.Errorf("%w: %s", ErrOffBoard, s)
+		}
+		return x, y, nil
+	}
+
+	if len(s) == 2 && s[1] >= 'a' && s[1] <= 'z' {
+		return ParseSGF(s, width, height)
+	}
+	return ParseGTP(s, width, height)
+}
+
+// returns the GTP vertex of a point, like "D4"
+func (b *Board) FormatGTP(p Point) string {
+	x, y := b.ToXY(p)
+	return FormatGTP(x, y, b.height)
+}
+
+// parses coords in GTP, SGF or numeric form into a point of this board
+func (b *Board) ParseCoord(s string) (Point, error) {
+	x, y, err := ParseCoord(s, b.width, b.height)
+	if err != nil {
+		return 0, err
+	}
+	return b.ToPoint(x, y), nil
+}
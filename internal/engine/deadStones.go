@@ -1,49 +1,18 @@
 package engine
 
 // returns set of points that contain dead stones
-func (b *Board) findDeadStones() map[Point]bool {
-	deadStones := make(map[Point]bool)
-	analyzed := make(map[Point]bool)
+func (b *Board) findDeadStones() Bitset {
+	var deadStones Bitset
 
-	// analyze each group once
-	for y := 1; y <= b.size; y++ {
-		for x := 1; x <= b.size; x++ {
-			p := b.ToPoint(x, y)
-			color := b.points[p]
-
-			if color == Empty || color == Border || analyzed[p] {
-				continue
-			}
-
-			// get the group this stone belongs to
-			group, ok := b.groups[p]
-			if !ok {
-				continue
-			}
-
-			// skip if already analyzed this group
-			alreadyChecked := false
-			for stone := range group.Stones {
-				if analyzed[stone] {
-					alreadyChecked = true
-					break
-				}
-			}
-			if alreadyChecked {
-				continue
-			}
-
-			// mark all stones in group as analyzed
-			for stone := range group.Stones {
-				analyzed[stone] = true
-			}
+	// analyze each group once, at its lowest stone
+	for i, group := range b.groups {
+		if group == nil || group.Stones.First() != Point(i) {
+			continue
+		}
 
-			// check if group is dead
-			if b.isGroupDead(group) {
-				for stone := range group.Stones {
-					deadStones[stone] = true
-				}
-			}
+		// check if group is dead
+		if b.isGroupDead(group) {
+			deadStones = deadStones.Or(group.Stones)
 		}
 	}
 
@@ -52,13 +21,15 @@ func (b *Board) findDeadStones() map[Point]bool {
 
 // checks if a group is dead (<2 eyes or surrounded)
 func (b *Board) isGroupDead(group *Group) bool {
+	liberties := group.Liberties.Count()
+
 	// if group has 2+ liberties, likely alive
-	if len(group.Liberties) >= 2 {
+	if liberties >= 2 {
 		return false
 	}
 
 	// if in atari (1 liberty), check if it's in enemy territory
-	if len(group.Liberties) == 1 {
+	if liberties == 1 {
 		return b.isInEnemyTerritory(group)
 	}
 
@@ -74,7 +45,7 @@ func (b *Board) isInEnemyTerritory(group *Group) bool {
 	}
 
 	// if any liberty connects to friendly territory or has space, not dead
-	for liberty := range group.Liberties {
+	for _, liberty := range group.Liberties.Points() {
 		// check neighbors of the liberty
 		friendlyInfluence := 0
 		enemyInfluence := 0
@@ -107,17 +78,17 @@ func (b *Board) isInEnemyTerritory(group *Group) bool {
 func (b *Board) removeDeadStones() *Board {
 	deadStones := b.findDeadStones()
 
-	if len(deadStones) == 0 {
+	if deadStones.IsEmpty() {
 		return b
 	}
 
 	// create new board without dead stones
 	newBoard := b.copy()
 
-	for deadPoint := range deadStones {
-		newBoard.points[deadPoint] = Empty
-		delete(newBoard.groups, deadPoint)
-	}
+	deadStones.ForEach(func(deadPoint Point) {
+		newBoard.removeStone(deadPoint)
+		newBoard.groups[deadPoint] = nil
+	})
 
 	// rebuild groups for remaining stones
 	newBoard.rebuildGroups()
@@ -127,24 +98,17 @@ func (b *Board) removeDeadStones() *Board {
 
 // rebuilds group structure after removing dead stones
 func (b *Board) rebuildGroups() {
-	b.groups = make(map[Point]*Group)
-	b.dsu = NewDSU(b.size * b.size * 2)
-	b.nextGroupID = 0
+	b.groups = make([]*Group, len(b.points))
 
 	// place all stones again to rebuild groups
-	for y := 1; y <= b.size; y++ {
-		for x := 1; x <= b.size; x++ {
+	for y := 1; y <= b.height; y++ {
+		for x := 1; x <= b.width; x++ {
 			p := b.ToPoint(x, y)
 			color := b.points[p]
 
 			if color == Black || color == White {
-				// create group for this stone
-				liberties := b.calculateInitialLiberties(p)
-				group := newGroup(b.nextGroupID, p, color, liberties)
-				b.nextGroupID++
-				b.groups[p] = group
-
-				// merge with adjacent friendly groups
+				// create group for this stone and merge with adjacent friendly groups
+				group := b.createNewGroup(p, color)
 				b.mergeFriendlyNeighbors(p, group)
 			}
 		}
@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// diagram characters, the first of each group is what String emits
+const (
+	blackChars = "X#@xB●"
+	whiteChars = "OoW○"
+	emptyChars = ".+,_·"
+	koChar     = '*' // empty point the side to move may not retake at
+)
+
+// builds a board from an ASCII diagram like the one String prints
+//
+// each row lists its points from the left: X # @ x B ● for black, O o W ○ for white,
+// . + , _ · for empty and * for an empty point held by a ko. spaces, row numbers,
+// column label lines, "$$" prefixes and | - + frame lines are ignored
+//
+// a ko point must be surrounded by one color with exactly one single stone in atari,
+// the stone that just captured there; retaking at once is then a ko violation
+func ParseDiagram(diagram string) (*Board, error) {
+	rows := make([][]Color, 0)
+	ko := [2]int{-1, -1}
+
+	for lineNo, line := range strings.Split(diagram, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "$$"))
+		if isFrameLine(line) || isLabelLine(line) {
+			continue
+		}
+
+		// row numbers may stand on either side
+		line = strings.TrimFunc(line, func(r rune) bool { return unicode.IsDigit(r) || unicode.IsSpace(r) || r == '|' })
+		if line == "" {
+			continue
+		}
+
+		row := make([]Color, 0, len(line))
+		for _, r := range line {
+			switch {
+			case unicode.IsSpace(r) || r == '|':
+				continue
+			case strings.ContainsRune(blackChars, r):
+				row = append(row, Black)
+			case strings.ContainsRune(whiteChars, r):
+				row = append(row, White)
+			case strings.ContainsRune(emptyChars, r):
+				row = append(row, Empty)
+			case r == koChar:
+				if ko[0] >= 0 {
+					return nil, fmt.Errorf("line %d: more than one ko point", lineNo+1)
+				}
+				ko = [2]int{len(row) + 1, len(rows) + 1}
+				row = append(row, Empty)
+			default:
+				return nil, fmt.Errorf("line %d: unexpected character %q", lineNo+1, r)
+			}
+		}
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return nil, fmt.Errorf("line %d: row has %d points, expected %d", lineNo+1, len(row), len(rows[0]))
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("empty diagram")
+	}
+	width, height := len(rows[0]), len(rows)
+	if err := ValidateSize(width, height); err != nil {
+		return nil, err
+	}
+
+	b := NewRectBoard(width, height)
+	for y, row := range rows {
+		for x, c := range row {
+			if c != Empty {
+				b.placeStone(b.ToPoint(x+1, y+1), c)
+			}
+		}
+	}
+	b.rebuildGroups()
+
+	// stones without liberties could never have been left on the board
+	for _, g := range b.Groups(Black) {
+		if g.Liberties.IsEmpty() {
+			return nil, fmt.Errorf("black group at %s has no liberties", b.FormatGTP(g.Stones.First()))
+		}
+	}
+	for _, g := range b.Groups(White) {
+		if g.Liberties.IsEmpty() {
+			return nil, fmt.Errorf("white group at %s has no liberties", b.FormatGTP(g.Stones.First()))
+		}
+	}
+
+	if ko[0] >= 0 {
+		if err := b.setKo(b.ToPoint(ko[0], ko[1])); err != nil {
+			return nil, err
+		}
+	}
+
+	b.history = b.history.extend(b.koHash)
+	return b, nil
+}
+
+// records the position before the capture that made a ko at p, so retaking is refused
+func (b *Board) setKo(p Point) error {
+	var capturer Point = -1
+	surrounding := Empty
+	for _, n := range b.Neighbors(p) {
+		c := b.points[n]
+		if c == Border {
+			continue
+		}
+		if c == Empty || (surrounding != Empty && c != surrounding) {
+			return fmt.Errorf("no ko at %s: it must be surrounded by one color", b.FormatGTP(p))
+		}
+		surrounding = c
+		if g := b.groups[n]; g.Stones.Count() == 1 && g.Liberties.Count() == 1 {
+			if capturer >= 0 {
+				return fmt.Errorf("no ko at %s: retaking would capture more than one stone", b.FormatGTP(p))
+			}
+			capturer = n
+		}
+	}
+	if capturer < 0 {
+		return fmt.Errorf("no ko at %s: no stone can be retaken", b.FormatGTP(p))
+	}
+
+	// before the capture, the captured stone stood at p and the capturer was not yet played
+	before := b.koHash ^ zobristKey(p, opposite(surrounding)) ^ zobristKey(capturer, surrounding)
+	b.history = b.history.extend(before)
+	b.koPoint = p
+	return nil
+}
+
+// reports whether a line is a diagram frame like "+-----+" or "-------"
+func isFrameLine(line string) bool {
+	return strings.Contains(line, "-") && strings.Trim(line, "-+| ") == ""
+}
+
+// reports whether a line holds column labels like "A B C D", which always start at A
+func isLabelLine(line string) bool {
+	line = strings.TrimLeft(line, " ")
+	return strings.HasPrefix(line, "A") || strings.HasPrefix(line, "a")
+}
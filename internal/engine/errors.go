@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errors returned for illegal moves, use errors.Is to tell them apart
+var (
+	ErrOccupied = errors.New("point is not empty")
+	ErrOffBoard = errors.New("point is off the board")
+	ErrSuicide  = errors.New("suicidal move: group has no liberties")
+	ErrKo       = errors.New("illegal move: Ko rule violation")
+)
+
+// KoError reports a move that recreates an earlier position, it matches ErrKo
+type KoError struct {
+	MoveNumber int // the repeated position is the one after this move, counting handicap stones
+}
+
+func (e *KoError) Error() string {
+	return fmt.Sprintf("%v: repeats the position after move %d", ErrKo, e.MoveNumber)
+}
+
+// makes errors.Is(err, ErrKo) hold for every KoError
+func (e *KoError) Is(target error) bool {
+	return target == ErrKo
+}
+
+// returns the error for a legality result, nil for Legal
+// ko results carry no move number here, see ApplyMove for that
+func (l Legality) Err() error {
+	switch l {
+	case Legal:
+		return nil
+	case Occupied:
+		return ErrOccupied
+	case OffBoard:
+		return ErrOffBoard
+	case Suicide:
+		return ErrSuicide
+	default:
+		return ErrKo
+	}
+}
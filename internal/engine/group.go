@@ -1,62 +1,38 @@
 package engine
 
 // Group is a chain of connected stones of the same color
+// ID is the point of the group's root stone, so IDs are bounded by the board area
+// and reused once a group is captured
 type Group struct {
-	ID        int
-	Stones    map[Point]struct{}
-	Liberties map[Point]struct{}
+	ID        Point
+	Stones    Bitset
+	Liberties Bitset
 	Color     Color
 }
 
 // creates new group for a single stone
-func newGroup(id int, point Point, color Color, liberties map[Point]struct{}) *Group {
-	stones := make(map[Point]struct{})
-	stones[point] = struct{}{}
-
-	return &Group{
+func newGroup(id Point, point Point, color Color, liberties Bitset) *Group {
+	g := &Group{
 		ID:        id,
-		Stones:    stones,
 		Liberties: liberties,
 		Color:     color,
 	}
+	g.Stones.Set(point)
+	return g
 }
 
 // copy creates a deep copy of the group
 func (g *Group) copy() *Group {
-	newStones := make(map[Point]struct{}, len(g.Stones))
-	for s := range g.Stones {
-		newStones[s] = struct{}{}
-	}
-
-	newLiberties := make(map[Point]struct{}, len(g.Liberties))
-	for l := range g.Liberties {
-		newLiberties[l] = struct{}{}
-	}
-
-	return &Group{
-		ID:        g.ID,
-		Stones:    newStones,
-		Liberties: newLiberties,
-		Color:     g.Color,
-	}
+	c := *g
+	return &c
 }
 
 // combines the stones and liberties of another group into this one. other group should be discarded after merge
 func (g *Group) mergeWith(other *Group) {
-	// add all stones from other group
-	for stone := range other.Stones {
-		g.Stones[stone] = struct{}{}
-	}
-
-	// add liberties from other group
-	for liberty := range other.Liberties {
-		g.Liberties[liberty] = struct{}{}
-	}
+	g.Stones = g.Stones.Or(other.Stones)
 
-	// remove any liberties that are now occupied by stones in the merged group
-	for stone := range g.Stones {
-		delete(g.Liberties, stone)
-	}
+	// liberties of both, minus any that are now occupied by stones in the merged group
+	g.Liberties = g.Liberties.Or(other.Liberties).AndNot(g.Stones)
 }
 
 // inits a new group for a stone, calc its initial liberties, and adds it to the board
@@ -64,43 +40,119 @@ func (b *Board) createNewGroup(p Point, c Color) *Group {
 	// calc initial liberties for the new stone
 	liberties := b.calculateInitialLiberties(p)
 
-	// create a new group for this stone
-	b.nextGroupID++
-	newGroup := newGroup(b.nextGroupID, p, c, liberties)
+	// create a new group rooted at this stone
+	newGroup := newGroup(p, p, c, liberties)
 	b.groups[p] = newGroup
 	return newGroup
 }
 
 // finds and merges the new group with any adjacent friendly groups
 func (b *Board) mergeFriendlyNeighbors(p Point, newGroup *Group) {
+	merged := newGroup
 	for _, n := range b.Neighbors(p) {
-		if b.points[n] == newGroup.Color {
-			neighborGroup := b.groups[n]
-			if neighborGroup != nil && b.dsu.Find(newGroup.ID) != b.dsu.Find(neighborGroup.ID) {
-				// find actual root groups by looking them up through any stone in the group
-				// use newly placed stone for newGroup and the neighbor stone for neighborGroup
-				rootGroup := b.groups[p]
-				neighborRootGroup := b.groups[n]
-
-				// DSU union
-				b.dsu.Union(newGroup.ID, neighborGroup.ID)
-				newRootID := b.dsu.Find(newGroup.ID)
-
-				var mergedGroup *Group
-				// group with new root ID absorbs the other
-				if newRootID == rootGroup.ID {
-					rootGroup.mergeWith(neighborRootGroup)
-					mergedGroup = rootGroup
-				} else {
-					neighborRootGroup.mergeWith(rootGroup)
-					mergedGroup = neighborRootGroup
-				}
-
-				// update the groups map for all stones in the merged group to point to the new single root group
-				for stonePoint := range mergedGroup.Stones {
-					b.groups[stonePoint] = mergedGroup
-				}
-			}
+		if b.points[n] != newGroup.Color {
+			continue
+		}
+
+		neighborGroup := b.groups[n]
+		if neighborGroup == nil || neighborGroup == merged {
+			continue
+		}
+
+		// larger group absorbs the smaller one and keeps its root, so fewer stones are relabeled
+		absorber, absorbed := merged, neighborGroup
+		if absorbed.Stones.Count() > absorber.Stones.Count() {
+			absorber, absorbed = absorbed, absorber
 		}
+		absorber.mergeWith(absorbed)
+
+		// point the absorbed stones at the merged group
+		absorbed.Stones.ForEach(func(stonePoint Point) {
+			b.groups[stonePoint] = absorber
+		})
+		merged = absorber
+	}
+}
+
+// returns no. of stones in the group
+func (g *Group) Size() int {
+	return g.Stones.Count()
+}
+
+// returns no. of liberties of the group
+func (g *Group) LibertyCount() int {
+	return g.Liberties.Count()
+}
+
+// returns whether the group has a single liberty left
+func (g *Group) InAtari() bool {
+	return g.Liberties.Count() == 1
+}
+
+// returns the color at a point of the padded grid, Border for the padding around the board
+func (b *Board) ColorAt(p Point) Color {
+	return b.points[p]
+}
+
+// returns a copy of the group holding a stone at p, false for empty points
+func (b *Board) GroupAt(p Point) (Group, bool) {
+	if p < 0 || int(p) >= len(b.groups) || b.groups[p] == nil {
+		return Group{}, false
+	}
+	return *b.groups[p], true
+}
+
+// returns the no. of liberties of the group at p (0 for empty points)
+func (b *Board) Liberties(p Point) int {
+	if p < 0 || int(p) >= len(b.groups) || b.groups[p] == nil {
+		return 0
+	}
+	return b.groups[p].Liberties.Count()
+}
+
+// returns copies of all groups of a color, ordered by their lowest stone
+func (b *Board) Groups(color Color) []Group {
+	stones := b.black
+	if color == White {
+		stones = b.white
+	} else if color != Black {
+		return nil
+	}
+	return b.groupsIn(stones)
+}
+
+// returns copies of the groups of a color with a single liberty
+func (b *Board) GroupsInAtari(color Color) []Group {
+	groups := b.Groups(color)
+	inAtari := groups[:0]
+	for _, g := range groups {
+		if g.InAtari() {
+			inAtari = append(inAtari, g)
+		}
+	}
+	return inAtari
+}
+
+// returns copies of the enemy groups touching the group at p, nil for empty points
+func (b *Board) AdjacentEnemyGroups(p Point) []Group {
+	group, ok := b.GroupAt(p)
+	if !ok {
+		return nil
+	}
+	enemies := b.white
+	if group.Color == White {
+		enemies = b.black
+	}
+	return b.groupsIn(group.Stones.neighbors(b.internalSize).And(enemies))
+}
+
+// returns copies of the groups holding the given stones, each once
+func (b *Board) groupsIn(stones Bitset) []Group {
+	groups := make([]Group, 0)
+	for !stones.IsEmpty() {
+		g := b.groups[stones.First()]
+		groups = append(groups, *g)
+		stones = stones.AndNot(g.Stones)
 	}
+	return groups
 }
@@ -0,0 +1,56 @@
+package engine
+
+import "fmt"
+
+// returns the fixed handicap points for n stones, in the order the GTP spec places them
+// boards need at least 7 lines, and odd dimensions of 9 or more for over 4 stones
+func (b *Board) HandicapPoints(n int) ([]Point, error) {
+	if n < 2 {
+		return nil, nil
+	}
+
+	// star points sit on the 4th line, or the 3rd on small boards
+	shortSide := min(b.width, b.height)
+	offset := 4
+	if shortSide < 13 {
+		offset = 3
+	}
+
+	maxStones := 4
+	if b.width%2 == 1 && b.height%2 == 1 && shortSide >= 9 {
+		maxStones = 9
+	}
+	if shortSide < 7 || n > maxStones {
+		return nil, fmt.Errorf("handicap %d not supported on %dx%d board", n, b.width, b.height)
+	}
+
+	low := offset
+	highX, highY := b.width-offset+1, b.height-offset+1
+	midX, midY := (b.width+1)/2, (b.height+1)/2
+
+	lowerLeft := b.ToPoint(low, highY)
+	upperRight := b.ToPoint(highX, low)
+	upperLeft := b.ToPoint(low, low)
+	lowerRight := b.ToPoint(highX, highY)
+	center := b.ToPoint(midX, midY)
+	leftSide := b.ToPoint(low, midY)
+	rightSide := b.ToPoint(highX, midY)
+	bottomSide := b.ToPoint(midX, highY)
+	topSide := b.ToPoint(midX, low)
+
+	points := []Point{lowerLeft, upperRight, upperLeft, lowerRight}
+	switch n {
+	case 2, 3, 4:
+		return points[:n], nil
+	case 5:
+		return append(points, center), nil
+	case 6:
+		return append(points, leftSide, rightSide), nil
+	case 7:
+		return append(points, leftSide, rightSide, center), nil
+	case 8:
+		return append(points, leftSide, rightSide, bottomSide, topSide), nil
+	default:
+		return append(points, leftSide, rightSide, bottomSide, topSide, center), nil
+	}
+}
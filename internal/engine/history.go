@@ -0,0 +1,83 @@
+package engine
+
+import "math/bits"
+
+// positionHistory is an immutable set of position hashes for superko detection
+// it is a hash trie where extending copies only the path to the new entry, so boards on
+// different lines of play share everything before the point where the lines branch
+type positionHistory struct {
+	root   *historyNode
+	length int // no. of positions recorded
+}
+
+// trie node, branching on 5 bits of the hash per level
+type historyNode struct {
+	bitmap  uint32         // which of the 32 branches are present
+	entries []historyEntry // the present branches in order
+}
+
+// a recorded hash or, if child is set, a subtree
+type historyEntry struct {
+	hash  uint64
+	index int // position in the history where hash first occurred
+	child *historyNode
+}
+
+// reports whether hash occurs in the history, and at which index
+func (h positionHistory) find(hash uint64) (int, bool) {
+	node := h.root
+	for shift := uint(0); node != nil; shift += 5 {
+		bit := uint32(1) << ((hash >> shift) & 31)
+		if node.bitmap&bit == 0 {
+			return 0, false
+		}
+		e := node.entries[bits.OnesCount32(node.bitmap&(bit-1))]
+		if e.child == nil {
+			return e.index, e.hash == hash
+		}
+		node = e.child
+	}
+	return 0, false
+}
+
+// returns the history followed by hash, the receiver is left unchanged
+func (h positionHistory) extend(hash uint64) positionHistory {
+	return positionHistory{root: h.root.insert(hash, h.length, 0), length: h.length + 1}
+}
+
+// returns a copy of the subtree with hash added at index, or n itself if hash is already there
+func (n *historyNode) insert(hash uint64, index int, shift uint) *historyNode {
+	bit := uint32(1) << ((hash >> shift) & 31)
+	if n == nil {
+		return &historyNode{bitmap: bit, entries: []historyEntry{{hash: hash, index: index}}}
+	}
+	pos := bits.OnesCount32(n.bitmap & (bit - 1))
+	if n.bitmap&bit == 0 {
+		entries := make([]historyEntry, 0, len(n.entries)+1)
+		entries = append(entries, n.entries[:pos]...)
+		entries = append(entries, historyEntry{hash: hash, index: index})
+		entries = append(entries, n.entries[pos:]...)
+		return &historyNode{bitmap: n.bitmap | bit, entries: entries}
+	}
+
+	e := n.entries[pos]
+	var replaced historyEntry
+	switch {
+	case e.child != nil:
+		child := e.child.insert(hash, index, shift+5)
+		if child == e.child {
+			return n
+		}
+		replaced = historyEntry{child: child}
+	case e.hash == hash:
+		return n // keep the first occurrence
+	default:
+		// two hashes share this branch, push both one level down
+		var child *historyNode
+		child = child.insert(e.hash, e.index, shift+5)
+		replaced = historyEntry{child: child.insert(hash, index, shift+5)}
+	}
+	entries := append([]historyEntry(nil), n.entries...)
+	entries[pos] = replaced
+	return &historyNode{bitmap: n.bitmap, entries: entries}
+}
@@ -0,0 +1,138 @@
+package engine
+
+// longest chase read before a ladder counts as escaped, well beyond any ladder on a 25x25 board
+const maxLadderDepth = 200
+
+// reports whether the group at p is captured in a ladder, with toMove playing first
+// the defender may only extend from its last liberty or capture a neighboring group in atari,
+// the attacker keeps playing atari on one of the two liberties; stones met along the way
+// (ladder breakers), captures and ko are all handled by actually playing the chase out
+// returns false for empty points and groups with more than 2 liberties
+func (pb *PlayoutBoard) IsLadderCaptured(p Point, toMove Color) bool {
+	defender := pb.points[p]
+	if defender != Black && defender != White {
+		return false
+	}
+
+	switch pb.libs[pb.head[p]] {
+	case 1:
+		if toMove != defender {
+			return true // captured right away
+		}
+		return !pb.ladderEscapes(p, 0)
+	case 2:
+		if toMove == defender {
+			return false // not in atari yet and the defender can add liberties first
+		}
+		return pb.ladderCaptures(p, 0)
+	default:
+		return false
+	}
+}
+
+// defender to move with the group at p in atari, reports whether it gets out
+func (pb *PlayoutBoard) ladderEscapes(p Point, depth int) bool {
+	if depth >= maxLadderDepth {
+		return true
+	}
+	defender := pb.points[p]
+
+	// candidate moves: capture a neighboring attacker group in atari, or extend
+	var buf [8]Point
+	moves := pb.atariCaptures(pb.head[p], buf[:0:7])
+	moves = pb.libertyPoints(pb.head[p], moves[:len(moves):len(moves)+1])
+
+	for _, m := range moves {
+		if !pb.Play(m, defender) {
+			continue
+		}
+		escaped := false
+		switch libs := pb.libs[pb.head[p]]; {
+		case libs >= 3:
+			escaped = true
+		case libs == 2:
+			escaped = !pb.ladderCaptures(p, depth+1)
+		}
+		pb.Undo()
+		if escaped {
+			return true
+		}
+	}
+	return false
+}
+
+// attacker to move with the group at p on 2 liberties, reports whether a chase captures it
+func (pb *PlayoutBoard) ladderCaptures(p Point, depth int) bool {
+	if depth >= maxLadderDepth {
+		return false
+	}
+	attacker := opposite(pb.points[p])
+
+	var buf [4]Point
+	for _, m := range pb.libertyPoints(pb.head[p], buf[:0]) {
+		if !pb.Play(m, attacker) {
+			continue
+		}
+		captured := pb.libs[pb.head[p]] == 1 && !pb.ladderEscapes(p, depth+1)
+		pb.Undo()
+		if captured {
+			return true
+		}
+	}
+	return false
+}
+
+// appends the liberties of the group at p to buf, at most cap(buf) of them
+// none for empty points
+func (pb *PlayoutBoard) LibertyPoints(p Point, buf []Point) []Point {
+	if c := pb.points[p]; c != Black && c != White {
+		return buf
+	}
+	return pb.libertyPoints(pb.head[p], buf)
+}
+
+// appends the empty points next to the group headed by h, at most cap(buf) of them
+func (pb *PlayoutBoard) libertyPoints(h Point, buf []Point) []Point {
+	for s := h; ; {
+		for _, n := range pb.Neighbors(s) {
+			if pb.points[n] == Empty && !containsPoint(buf, n) {
+				if len(buf) == cap(buf) {
+					return buf
+				}
+				buf = append(buf, n)
+			}
+		}
+		s = pb.next[s]
+		if s == h {
+			return buf
+		}
+	}
+}
+
+// appends the last liberties of enemy groups in atari next to the group headed by h
+func (pb *PlayoutBoard) atariCaptures(h Point, buf []Point) []Point {
+	enemy := opposite(pb.points[h])
+	for s := h; ; {
+		for _, n := range pb.Neighbors(s) {
+			if pb.points[n] == enemy && pb.libs[pb.head[n]] == 1 {
+				var lib [1]Point
+				if l := pb.libertyPoints(pb.head[n], lib[:0]); len(l) == 1 && !containsPoint(buf, l[0]) && len(buf) < cap(buf) {
+					buf = append(buf, l[0])
+				}
+			}
+		}
+		s = pb.next[s]
+		if s == h {
+			return buf
+		}
+	}
+}
+
+// reports whether the group at p is captured in a ladder, with toMove playing first
+// see PlayoutBoard.IsLadderCaptured, reads on a scratch copy so the board is unchanged
+func (b *Board) IsLadderCaptured(p Point, toMove Color) bool {
+	if p < 0 || int(p) >= len(b.groups) || b.groups[p] == nil || b.groups[p].Liberties.Count() > 2 {
+		return false
+	}
+	return NewPlayoutBoard(b).IsLadderCaptured(p, toMove)
+}
@@ -0,0 +1,123 @@
+package engine
+
+// Legality is the result of checking a move, Legal or the reason it is illegal
+type Legality int
+
+const (
+	Legal Legality = iota
+	Occupied
+	OffBoard
+	Suicide
+	KoViolation // recreates an earlier position (positional superko)
+)
+
+// returns a short description of the result
+func (l Legality) String() string {
+	switch l {
+	case Legal:
+		return "legal"
+	case Occupied:
+		return "point is not empty"
+	case OffBoard:
+		return "point is off the board"
+	case Suicide:
+		return "suicide"
+	case KoViolation:
+		return "ko"
+	default:
+		return "unknown"
+	}
+}
+
+// checks whether color may play at p, without applying the move
+// captures and the resulting hash are worked out from the neighboring groups,
+// so no board is copied
+func (b *Board) CheckLegality(p Point, color Color) Legality {
+	if p < 0 || int(p) >= len(b.points) || b.points[p] == Border {
+		return OffBoard
+	}
+	if b.points[p] != Empty {
+		return Occupied
+	}
+
+	hasLiberty := false
+	var captured Bitset
+	for _, n := range b.Neighbors(p) {
+		switch b.points[n] {
+		case Empty:
+			hasLiberty = true
+		case Border:
+		default:
+			group := b.groups[n]
+			if group == nil {
+				continue
+			}
+			// p is one of the group's liberties, so a count of 1 means p is the last
+			lastLiberty := group.Liberties.Count() == 1
+			if group.Color == color && !lastLiberty {
+				hasLiberty = true
+			} else if group.Color != color && lastLiberty {
+				captured = captured.Or(group.Stones)
+			}
+		}
+	}
+
+	if captured.IsEmpty() && !hasLiberty {
+		return Suicide
+	}
+
+	// hash of the resulting position
+	hash := b.koHash ^ zobristKey(p, color)
+	enemy := opposite(color)
+	captured.ForEach(func(stone Point) {
+		hash ^= zobristKey(stone, enemy)
+	})
+	if _, repeated := b.history.find(hash); repeated {
+		return KoViolation
+	}
+	return Legal
+}
+
+// reports whether color may play at p
+func (b *Board) IsLegal(p Point, color Color) bool {
+	return b.CheckLegality(p, color) == Legal
+}
+
+// returns every point where color may play, row by row from the top left
+func (b *Board) LegalMoves(color Color) []Point {
+	moves := make([]Point, 0, b.width*b.height)
+	empty := b.emptyPoints()
+	empty.ForEach(func(p Point) {
+		if b.CheckLegality(p, color) == Legal {
+			moves = append(moves, p)
+		}
+	})
+	return moves
+}
+
+// returns the liberties the group of a stone played by color at p would have,
+// counting points freed by captures; 0 for occupied points. the move is not played
+func (b *Board) LibertiesAfter(p Point, color Color) int {
+	if p < 0 || int(p) >= len(b.points) || b.points[p] != Empty {
+		return 0
+	}
+
+	var stones, captured Bitset
+	stones.Set(p)
+	for _, n := range b.Neighbors(p) {
+		group := b.groups[n]
+		if group == nil {
+			continue
+		}
+		if group.Color == color {
+			stones = stones.Or(group.Stones)
+		} else if group.Liberties.Count() == 1 {
+			captured = captured.Or(group.Stones)
+		}
+	}
+
+	free := b.emptyPoints().Or(captured)
+	free.Clear(p)
+	libs := stones.neighbors(b.internalSize).And(free)
+	return libs.Count()
+}
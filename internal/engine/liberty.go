@@ -1,11 +1,11 @@
 package engine
 
 // calc liberties for a newly placed stone
-func (b *Board) calculateInitialLiberties(p Point) map[Point]struct{} {
-	liberties := make(map[Point]struct{})
+func (b *Board) calculateInitialLiberties(p Point) Bitset {
+	var liberties Bitset
 	for _, n := range b.Neighbors(p) {
 		if b.points[n] == Empty {
-			liberties[n] = struct{}{}
+			liberties.Set(n)
 		}
 	}
 	return liberties
@@ -19,7 +19,7 @@ func (b *Board) updateEnemyLiberties(p Point, placedColor Color) {
 			enemyGroup := b.groups[n]
 			if enemyGroup != nil {
 				// a liberty is taken away from this group
-				delete(enemyGroup.Liberties, p)
+				enemyGroup.Liberties.Clear(p)
 			}
 		}
 	}
@@ -1,17 +1,18 @@
 package engine
 
-import "errors"
-
 // Move is a player's action, placing a stone on the board
 type Move struct {
 	Point Point
 	Color Color
 }
 
-// check if a move is on an empty point
+// check if a move is on an empty point of the board
 func (b *Board) validatePlacement(m Move) error {
+	if m.Point < 0 || int(m.Point) >= len(b.points) || b.points[m.Point] == Border {
+		return ErrOffBoard
+	}
 	if b.points[m.Point] != Empty {
-		return errors.New("point is not empty")
+		return ErrOccupied
 	}
 	return nil
 }
@@ -20,8 +21,8 @@ func (b *Board) validatePlacement(m Move) error {
 // this is called AFTER captures have been resolved
 func (b *Board) validateSuicide(p Point) error {
 	group := b.groups[p]
-	if group != nil && len(group.Liberties) == 0 {
-		return errors.New("suicidal move: group has no liberties")
+	if group != nil && group.Liberties.IsEmpty() {
+		return ErrSuicide
 	}
 	return nil
 }
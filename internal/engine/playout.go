@@ -0,0 +1,550 @@
+package engine
+
+// PlayoutBoard is a mutable board for fast random playouts and tree searches
+// moves are played in place and can be taken back with Undo, so no copies are made
+// only simple ko is enforced (no superko history)
+type PlayoutBoard struct {
+	points       []Color
+	width        int
+	height       int
+	internalSize int
+	next         []Point // next stone of the same group (circular list)
+	head         []Point // representative stone of the group each stone belongs to
+	libs         []int   // exact liberty count, only valid at group heads
+	koPoint      Point   // point that cannot be retaken this turn, -1 if none
+	koColor      Color   // player the ko point is banned for, the one whose stone was taken
+	hash         uint64
+
+	undoStack []playoutUndo
+	captured  []Point // captured stones, sliced by undo records
+
+	mark    []uint32 // scratch marks for rebuilds and scoring
+	markGen uint32
+	libMark []uint32 // scratch marks for liberty counting
+	libGen  uint32
+}
+
+// state needed to take back a single move
+type playoutUndo struct {
+	point         Point
+	color         Color
+	prevKo        Point
+	prevKoColor   Color
+	prevHash      uint64
+	capturedStart int
+}
+
+// NewPlayoutBoard creates a mutable playout board from a board position
+func NewPlayoutBoard(b *Board) *PlayoutBoard {
+	n := len(b.points)
+	pb := &PlayoutBoard{
+		points:       make([]Color, n),
+		width:        b.width,
+		height:       b.height,
+		internalSize: b.internalSize,
+		next:         make([]Point, n),
+		head:         make([]Point, n),
+		libs:         make([]int, n),
+		koPoint:      b.koPoint,
+		hash:         b.koHash,
+		undoStack:    make([]playoutUndo, 0, 64),
+		captured:     make([]Point, 0, 64),
+		mark:         make([]uint32, n),
+		libMark:      make([]uint32, n),
+	}
+	copy(pb.points, b.points)
+
+	// the stones around a ko point belong to the player who took the ko, who may fill it
+	if pb.koPoint >= 0 {
+		for _, n := range pb.Neighbors(pb.koPoint) {
+			if c := pb.points[n]; c == Black || c == White {
+				pb.koColor = opposite(c)
+			}
+		}
+	}
+
+	// build groups for all stones
+	gen := pb.nextMark()
+	for i := range pb.points {
+		p := Point(i)
+		if (pb.points[p] == Black || pb.points[p] == White) && pb.mark[p] != gen {
+			pb.rebuildGroup(p, gen)
+		}
+	}
+
+	return pb
+}
+
+// returns board size, the width on rectangular boards
+func (pb *PlayoutBoard) Size() int {
+	return pb.width
+}
+
+// returns no. of columns
+func (pb *PlayoutBoard) Width() int {
+	return pb.width
+}
+
+// returns no. of rows
+func (pb *PlayoutBoard) Height() int {
+	return pb.height
+}
+
+// returns the zobrist hash of the curr position
+func (pb *PlayoutBoard) Hash() uint64 {
+	return pb.hash
+}
+
+// returns the active ko point, -1 if none
+func (pb *PlayoutBoard) KoPoint() Point {
+	return pb.koPoint
+}
+
+// convert 1-based (x, y) coords to a Point
+func (pb *PlayoutBoard) ToPoint(x, y int) Point {
+	return Point(y*pb.internalSize + x)
+}
+
+// convert a Point to 1-based (x, y) coords
+func (pb *PlayoutBoard) ToXY(p Point) (int, int) {
+	return int(p) % pb.internalSize, int(p) / pb.internalSize
+}
+
+// returns the color at a point
+func (pb *PlayoutBoard) ColorAt(p Point) Color {
+	return pb.points[p]
+}
+
+// returns the 4 direct neighbors of a point
+func (pb *PlayoutBoard) Neighbors(p Point) [4]Point {
+	internalSize := Point(pb.internalSize)
+	return [4]Point{p - 1, p + 1, p - internalSize, p + internalSize}
+}
+
+// returns the no. of liberties of the group at p (0 for empty points)
+func (pb *PlayoutBoard) Liberties(p Point) int {
+	c := pb.points[p]
+	if c != Black && c != White {
+		return 0
+	}
+	return pb.libs[pb.head[p]]
+}
+
+// returns the no. of moves that can be undone
+func (pb *PlayoutBoard) Depth() int {
+	return len(pb.undoStack)
+}
+
+// checks if a move is legal without changing the board
+func (pb *PlayoutBoard) IsLegal(p Point, color Color) bool {
+	if p < 0 || int(p) >= len(pb.points) || pb.points[p] != Empty || (p == pb.koPoint && color == pb.koColor) {
+		return false
+	}
+
+	for _, n := range pb.Neighbors(p) {
+		switch pb.points[n] {
+		case Empty:
+			return true // direct liberty
+		case color:
+			if pb.libs[pb.head[n]] > 1 {
+				return true // connects to a group with spare liberties
+			}
+		case Border:
+		default:
+			if pb.libs[pb.head[n]] == 1 {
+				return true // captures
+			}
+		}
+	}
+
+	// suicide
+	return false
+}
+
+// plays a move in place, returns false (and leaves the board untouched) if illegal
+func (pb *PlayoutBoard) Play(p Point, color Color) bool {
+	if !pb.IsLegal(p, color) {
+		return false
+	}
+
+	pb.undoStack = append(pb.undoStack, playoutUndo{
+		point:         p,
+		color:         color,
+		prevKo:        pb.koPoint,
+		prevKoColor:   pb.koColor,
+		prevHash:      pb.hash,
+		capturedStart: len(pb.captured),
+	})
+
+	// place stone as a new single-stone group
+	pb.points[p] = color
+	pb.hash ^= zobristKey(p, color)
+	pb.head[p] = p
+	pb.next[p] = p
+	pb.libs[p] = 0
+
+	// collect distinct neighbor groups
+	var enemies [4]Point
+	enemyCount := 0
+	for _, n := range pb.Neighbors(p) {
+		switch pb.points[n] {
+		case Empty:
+			pb.libs[p]++
+		case color:
+			h := pb.head[n]
+			if target := pb.head[p]; h != target {
+				if target == p {
+					// lone new stone joins the existing group
+					pb.mergeGroups(h, p)
+				} else {
+					pb.mergeGroups(target, h)
+				}
+			}
+		case Border:
+		default:
+			h := pb.head[n]
+			if !containsPoint(enemies[:enemyCount], h) {
+				enemies[enemyCount] = h
+				enemyCount++
+			}
+		}
+	}
+
+	// merged groups need an exact recount
+	if pb.next[p] != p {
+		pb.libs[pb.head[p]] = pb.countLiberties(pb.head[p])
+	}
+
+	// enemy groups lose this point as a liberty, capture those left without any
+	capturedBefore := len(pb.captured)
+	for _, h := range enemies[:enemyCount] {
+		pb.libs[h]--
+		if pb.libs[h] == 0 {
+			pb.removeGroup(h)
+		}
+	}
+
+	// simple ko: a single stone captured a single stone and is left in atari
+	pb.koPoint = -1
+	if len(pb.captured)-capturedBefore == 1 && pb.next[p] == p && pb.libs[p] == 1 {
+		pb.koPoint = pb.captured[capturedBefore]
+		pb.koColor = opposite(color)
+	}
+
+	return true
+}
+
+// plays a pass, which only clears the ko point. undone with Undo like a move
+func (pb *PlayoutBoard) Pass(color Color) {
+	pb.undoStack = append(pb.undoStack, playoutUndo{
+		point:         -1,
+		color:         color,
+		prevKo:        pb.koPoint,
+		prevKoColor:   pb.koColor,
+		prevHash:      pb.hash,
+		capturedStart: len(pb.captured),
+	})
+	pb.koPoint = -1
+}
+
+// takes back the last move or pass, returns false if there is nothing to undo
+func (pb *PlayoutBoard) Undo() bool {
+	if len(pb.undoStack) == 0 {
+		return false
+	}
+
+	u := pb.undoStack[len(pb.undoStack)-1]
+	pb.undoStack = pb.undoStack[:len(pb.undoStack)-1]
+
+	pb.koPoint, pb.koColor = u.prevKo, u.prevKoColor
+	pb.hash = u.prevHash
+	if u.point < 0 {
+		return true
+	}
+
+	// remove placed stone and restore captured ones
+	pb.points[u.point] = Empty
+	captured := pb.captured[u.capturedStart:]
+	enemy := opposite(u.color)
+	for _, s := range captured {
+		pb.points[s] = enemy
+	}
+
+	// regroup everything that touched the changed points
+	gen := pb.nextMark()
+	for _, n := range pb.Neighbors(u.point) {
+		pb.rebuildGroup(n, gen)
+	}
+	for _, s := range captured {
+		pb.rebuildGroup(s, gen)
+		for _, n := range pb.Neighbors(s) {
+			pb.rebuildGroup(n, gen)
+		}
+	}
+
+	pb.captured = pb.captured[:u.capturedStart]
+	return true
+}
+
+// returns stones captured by the last move (valid until the next Play or Undo)
+func (pb *PlayoutBoard) LastCaptures() []Point {
+	if len(pb.undoStack) == 0 {
+		return nil
+	}
+	return pb.captured[pb.undoStack[len(pb.undoStack)-1].capturedStart:]
+}
+
+// computes the Chinese area score of the curr position
+// uses the same dead stone heuristic as Board.CalculateChineseScore
+func (pb *PlayoutBoard) CalculateScoreWithKomi(komi float64) (black float64, white float64, winner Color) {
+	dead := pb.nextMark()
+	for i, c := range pb.points {
+		p := Point(i)
+		if (c == Black || c == White) && pb.head[p] == p && pb.isGroupDead(p) {
+			for s := p; ; {
+				pb.mark[s] = dead
+				s = pb.next[s]
+				if s == p {
+					break
+				}
+			}
+		}
+	}
+
+	// color of each point once dead stones are lifted
+	colorOf := func(p Point) Color {
+		if pb.mark[p] == dead {
+			return Empty
+		}
+		return pb.points[p]
+	}
+
+	blackScore, whiteScore := 0, 0
+	visited := make([]bool, len(pb.points))
+	queue := make([]Point, 0, 32)
+	for y := 1; y <= pb.height; y++ {
+		for x := 1; x <= pb.width; x++ {
+			p := pb.ToPoint(x, y)
+			if visited[p] {
+				continue
+			}
+
+			switch colorOf(p) {
+			case Black:
+				blackScore++
+			case White:
+				whiteScore++
+			case Empty:
+				// flood fill territory
+				queue = append(queue[:0], p)
+				visited[p] = true
+				area := 0
+				bordersBlack, bordersWhite := false, false
+				for len(queue) > 0 {
+					cur := queue[len(queue)-1]
+					queue = queue[:len(queue)-1]
+					area++
+					for _, n := range pb.Neighbors(cur) {
+						switch colorOf(n) {
+						case Empty:
+							if !visited[n] {
+								visited[n] = true
+								queue = append(queue, n)
+							}
+						case Black:
+							bordersBlack = true
+						case White:
+							bordersWhite = true
+						}
+					}
+				}
+				if bordersBlack && !bordersWhite {
+					blackScore += area
+				} else if bordersWhite && !bordersBlack {
+					whiteScore += area
+				}
+			}
+		}
+	}
+
+	black = float64(blackScore)
+	white = float64(whiteScore) + komi
+	if black > white {
+		return black, white, Black
+	} else if white > black {
+		return black, white, White
+	}
+	return black, white, Empty
+}
+
+// mirrors Board.isGroupDead for a group head
+func (pb *PlayoutBoard) isGroupDead(h Point) bool {
+	libs := pb.libs[h]
+	if libs >= 2 {
+		return false
+	}
+	if libs == 0 {
+		return true
+	}
+
+	// find the single liberty and check whether it has room or friendly support
+	color := pb.points[h]
+	enemy := opposite(color)
+	for s := h; ; {
+		for _, l := range pb.Neighbors(s) {
+			if pb.points[l] != Empty {
+				continue
+			}
+			friendly, hostile := 0, 0
+			for _, n := range pb.Neighbors(l) {
+				switch pb.points[n] {
+				case color:
+					friendly++
+				case enemy:
+					hostile++
+				case Empty:
+					return false
+				}
+			}
+			if friendly > hostile {
+				return false
+			}
+		}
+		s = pb.next[s]
+		if s == h {
+			break
+		}
+	}
+	return true
+}
+
+// joins group b into group a
+func (pb *PlayoutBoard) mergeGroups(a, b Point) {
+	for s := b; ; {
+		pb.head[s] = a
+		s = pb.next[s]
+		if s == b {
+			break
+		}
+	}
+	// splice the two circular lists
+	pb.next[a], pb.next[b] = pb.next[b], pb.next[a]
+}
+
+// removes a captured group from the board
+func (pb *PlayoutBoard) removeGroup(h Point) {
+	color := pb.points[h]
+	start := len(pb.captured)
+	for s := h; ; {
+		pb.points[s] = Empty
+		pb.hash ^= zobristKey(s, color)
+		pb.captured = append(pb.captured, s)
+		s = pb.next[s]
+		if s == h {
+			break
+		}
+	}
+
+	// each freed point is a new liberty for every distinct group touching it
+	for _, s := range pb.captured[start:] {
+		var seen [4]Point
+		seenCount := 0
+		for _, n := range pb.Neighbors(s) {
+			if pb.points[n] != Black && pb.points[n] != White {
+				continue
+			}
+			nh := pb.head[n]
+			if !containsPoint(seen[:seenCount], nh) {
+				seen[seenCount] = nh
+				seenCount++
+				pb.libs[nh]++
+			}
+		}
+	}
+}
+
+// counts distinct empty points next to a group
+func (pb *PlayoutBoard) countLiberties(h Point) int {
+	pb.libGen++
+	if pb.libGen == 0 {
+		for i := range pb.libMark {
+			pb.libMark[i] = 0
+		}
+		pb.libGen = 1
+	}
+	gen := pb.libGen
+	count := 0
+	for s := h; ; {
+		for _, n := range pb.Neighbors(s) {
+			if pb.points[n] == Empty && pb.libMark[n] != gen {
+				pb.libMark[n] = gen
+				count++
+			}
+		}
+		s = pb.next[s]
+		if s == h {
+			break
+		}
+	}
+	return count
+}
+
+// flood fills the group containing p and rebuilds its list and liberty count
+// stones already marked with gen are skipped so callers can rebuild many groups in one pass
+func (pb *PlayoutBoard) rebuildGroup(p Point, gen uint32) {
+	color := pb.points[p]
+	if (color != Black && color != White) || pb.mark[p] == gen {
+		return
+	}
+
+	pb.mark[p] = gen
+	pb.head[p] = p
+	pb.next[p] = p
+	stack := []Point{p}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, n := range pb.Neighbors(cur) {
+			if pb.points[n] == color && pb.mark[n] != gen {
+				pb.mark[n] = gen
+				pb.head[n] = p
+				// insert after head
+				pb.next[n] = pb.next[p]
+				pb.next[p] = n
+				stack = append(stack, n)
+			}
+		}
+	}
+
+	pb.libs[p] = pb.countLiberties(p)
+}
+
+// returns a fresh mark generation
+func (pb *PlayoutBoard) nextMark() uint32 {
+	pb.markGen++
+	if pb.markGen == 0 {
+		// wrapped around, clear stale marks
+		for i := range pb.mark {
+			pb.mark[i] = 0
+		}
+		pb.markGen = 1
+	}
+	return pb.markGen
+}
+
+func containsPoint(points []Point, p Point) bool {
+	for _, q := range points {
+		if q == p {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the other player's color
+func opposite(c Color) Color {
+	if c == Black {
+		return White
+	}
+	return Black
+}
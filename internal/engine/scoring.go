@@ -15,45 +15,36 @@ type Score struct {
 // uses half-counting (total_points = black_score + white_score + dame)
 func (b *Board) CalculateChineseScore() Score {
 	// remove dead stones first
-	scoringBoard := b.removeDeadStones()
-
-	score := Score{}
-	visited := make(map[Point]bool)
-
-	// count stones and territories
-	for y := 1; y <= scoringBoard.size; y++ {
-		for x := 1; x <= scoringBoard.size; x++ {
-			p := scoringBoard.ToPoint(x, y)
-
-			if visited[p] {
-				continue
-			}
-
-			color := scoringBoard.points[p]
-
-			switch color {
-			case Black:
-				score.BlackStones++
-				visited[p] = true
+	return b.removeDeadStones().areaScore()
+}
 
-			case White:
-				score.WhiteStones++
-				visited[p] = true
+// computes the Tromp-Taylor area score, every stone on the board counts as alive
+func (b *Board) CalculateTrompTaylorScore() Score {
+	return b.areaScore()
+}
 
-			case Empty:
-				// find territory using flood fill
-				territory, owner := scoringBoard.floodFillTerritory(p, visited)
-				territorySize := len(territory)
+// counts stones and the empty regions reaching only one color
+func (b *Board) areaScore() Score {
+	score := Score{
+		BlackStones: b.black.Count(),
+		WhiteStones: b.white.Count(),
+	}
 
-				switch owner {
-				case Black:
-					score.BlackArea += territorySize
-				case White:
-					score.WhiteArea += territorySize
-				default:
-					score.DamePoints += territorySize
-				}
-			}
+	// count territories, one empty region at a time
+	empty := b.emptyPoints()
+	remaining := empty
+	for !remaining.IsEmpty() {
+		territory, owner := b.floodFillTerritory(remaining.First(), empty)
+		remaining = remaining.AndNot(territory)
+		territorySize := territory.Count()
+
+		switch owner {
+		case Black:
+			score.BlackArea += territorySize
+		case White:
+			score.WhiteArea += territorySize
+		default:
+			score.DamePoints += territorySize
 		}
 	}
 
@@ -66,10 +57,13 @@ func (b *Board) CalculateChineseScore() Score {
 
 // computes score with komi (handicap)
 func (b *Board) CalculateScoreWithKomi(komi float64) (black float64, white float64, winner Color) {
-	score := b.CalculateChineseScore()
+	return b.CalculateChineseScore().WithKomi(komi)
+}
 
-	blackScore := float64(score.Black)
-	whiteScore := float64(score.White) + komi
+// adds komi to white's score and decides the winner (Empty on a draw)
+func (s Score) WithKomi(komi float64) (black float64, white float64, winner Color) {
+	blackScore := float64(s.Black)
+	whiteScore := float64(s.White) + komi
 
 	if blackScore > whiteScore {
 		return blackScore, whiteScore, Black
@@ -79,47 +73,25 @@ func (b *Board) CalculateScoreWithKomi(komi float64) (black float64, white float
 	return blackScore, whiteScore, Empty // draw
 }
 
-// uses BFS to find connected empty territory and determine its owner
+// grows the empty region containing start and determines its owner
 // returns territory points and color that owns it (or Empty if neutral/dame)
-func (b *Board) floodFillTerritory(start Point, visited map[Point]bool) ([]Point, Color) {
-	territory := []Point{}
-	queue := []Point{start}
-	visited[start] = true
-
-	var owner Color = Empty
-	bordersBlack := false
-	bordersWhite := false
-
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
-		territory = append(territory, current)
+func (b *Board) floodFillTerritory(start Point, empty Bitset) (Bitset, Color) {
+	var seed Bitset
+	seed.Set(start)
+	territory := seed.floodFill(empty, b.internalSize)
 
-		// check all neighbors
-		for _, n := range b.Neighbors(current) {
-			neighborColor := b.points[n]
-
-			if neighborColor == Empty && !visited[n] {
-				// continue flood fill into empty neighbor
-				visited[n] = true
-				queue = append(queue, n)
-			} else if neighborColor == Black {
-				bordersBlack = true
-			} else if neighborColor == White {
-				bordersWhite = true
-			}
-		}
-	}
+	// check which colors border this territory
+	border := territory.neighbors(b.internalSize)
+	bordersBlack := border.Intersects(&b.black)
+	bordersWhite := border.Intersects(&b.white)
 
 	// determine owner based on which colors border this territory
 	if bordersBlack && !bordersWhite {
-		owner = Black
+		return territory, Black
 	} else if bordersWhite && !bordersBlack {
-		owner = White
-	} else {
-		// borders both colors or neither - neutral territory (dame)
-		owner = Empty
+		return territory, White
 	}
 
-	return territory, owner
+	// borders both colors or neither - neutral territory (dame)
+	return territory, Empty
 }
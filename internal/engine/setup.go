@@ -0,0 +1,61 @@
+package engine
+
+import "fmt"
+
+// Setup lists stones to add or remove regardless of the rules, like SGF AB/AW/AE
+type Setup struct {
+	Black []Point // points to hold black stones
+	White []Point // points to hold white stones
+	Empty []Point // points to clear
+}
+
+// applies a setup to a copy of the board and returns it
+// groups and hash are recomputed, the ko point is cleared and the new position is
+// added to the superko history; setups leaving a group without liberties are refused
+func (b *Board) ApplySetup(setup Setup) (*Board, error) {
+	newBoard := b.copy()
+	newBoard.koPoint = -1
+
+	var seen Bitset
+	edits := []struct {
+		points []Point
+		color  Color
+	}{{setup.Black, Black}, {setup.White, White}, {setup.Empty, Empty}}
+	for _, edit := range edits {
+		for _, p := range edit.points {
+			if p < 0 || int(p) >= len(newBoard.points) || newBoard.points[p] == Border {
+				return nil, fmt.Errorf("%w: setup point %d", ErrOffBoard, p)
+			}
+			if seen.Has(p) {
+				return nil, fmt.Errorf("setup lists %s more than once", newBoard.FormatGTP(p))
+			}
+			seen.Set(p)
+
+			newBoard.points[p] = edit.color
+		}
+	}
+
+	// rebuild the stone sets, groups and hash from scratch
+	newBoard.black, newBoard.white = Bitset{}, Bitset{}
+	newBoard.onBoard.ForEach(func(p Point) {
+		switch newBoard.points[p] {
+		case Black:
+			newBoard.black.Set(p)
+		case White:
+			newBoard.white.Set(p)
+		}
+	})
+	newBoard.rebuildGroups()
+	newBoard.koHash = newBoard.computeHash()
+
+	for _, color := range []Color{Black, White} {
+		for _, g := range newBoard.Groups(color) {
+			if g.Liberties.IsEmpty() {
+				return nil, fmt.Errorf("setup leaves the group at %s without liberties", newBoard.FormatGTP(g.Stones.First()))
+			}
+		}
+	}
+
+	newBoard.history = newBoard.history.extend(newBoard.koHash)
+	return newBoard, nil
+}
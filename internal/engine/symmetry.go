@@ -0,0 +1,166 @@
+package engine
+
+// Symmetry is one of the 8 rotations and reflections of the board
+// rotations by 90 degrees and the diagonal reflections swap width and height
+type Symmetry int
+
+const (
+	Identity Symmetry = iota
+	Rotate90          // clockwise
+	Rotate180
+	Rotate270        // clockwise, i.e. 90 counterclockwise
+	FlipHorizontal   // mirror left and right
+	FlipVertical     // mirror top and bottom
+	FlipDiagonal     // transpose, top left stays in place
+	FlipAntiDiagonal // top right stays in place
+)
+
+// AllSymmetries lists every symmetry, Identity first
+var AllSymmetries = [8]Symmetry{
+	Identity, Rotate90, Rotate180, Rotate270,
+	FlipHorizontal, FlipVertical, FlipDiagonal, FlipAntiDiagonal,
+}
+
+// returns the symmetry that undoes s
+func (s Symmetry) Inverse() Symmetry {
+	switch s {
+	case Rotate90:
+		return Rotate270
+	case Rotate270:
+		return Rotate90
+	default:
+		return s
+	}
+}
+
+// returns whether s swaps width and height
+func (s Symmetry) SwapsAxes() bool {
+	return s == Rotate90 || s == Rotate270 || s == FlipDiagonal || s == FlipAntiDiagonal
+}
+
+// maps 1-based (x, y) coords on a width x height board to coords on the transformed board
+func (s Symmetry) TransformXY(x, y, width, height int) (int, int) {
+	switch s {
+	case Rotate90:
+		return height - y + 1, x
+	case Rotate180:
+		return width - x + 1, height - y + 1
+	case Rotate270:
+		return y, width - x + 1
+	case FlipHorizontal:
+		return width - x + 1, y
+	case FlipVertical:
+		return x, height - y + 1
+	case FlipDiagonal:
+		return y, x
+	case FlipAntiDiagonal:
+		return height - y + 1, width - x + 1
+	default:
+		return x, y
+	}
+}
+
+// returns the symmetries that keep the board dimensions, all 8 on square boards
+func (b *Board) sizeSymmetries() []Symmetry {
+	if b.width == b.height {
+		return AllSymmetries[:]
+	}
+	return []Symmetry{Identity, Rotate180, FlipHorizontal, FlipVertical}
+}
+
+// maps a point to the matching point of the board transformed by s
+func (b *Board) TransformPoint(p Point, s Symmetry) Point {
+	if p < 0 {
+		return p
+	}
+	x, y := b.ToXY(p)
+	tx, ty := s.TransformXY(x, y, b.width, b.height)
+	stride := b.width + 2
+	if s.SwapsAxes() {
+		stride = b.height + 2
+	}
+	return Point(ty*stride + tx)
+}
+
+// maps a move to the matching move of the board transformed by s, passes stay passes
+func (b *Board) TransformMove(m Move, s Symmetry) Move {
+	return Move{Point: b.TransformPoint(m.Point, s), Color: m.Color}
+}
+
+// returns a new board holding the position transformed by s
+// the superko history is not carried over, the new board starts a fresh one
+func (b *Board) Transform(s Symmetry) *Board {
+	width, height := b.width, b.height
+	if s.SwapsAxes() {
+		width, height = height, width
+	}
+
+	t := NewRectBoard(width, height)
+	b.black.ForEach(func(p Point) {
+		t.placeStone(b.TransformPoint(p, s), Black)
+	})
+	b.white.ForEach(func(p Point) {
+		t.placeStone(b.TransformPoint(p, s), White)
+	})
+	t.rebuildGroups()
+	if b.koPoint >= 0 {
+		t.koPoint = b.TransformPoint(b.koPoint, s)
+	}
+
+	t.history = t.history.extend(t.koHash)
+	return t
+}
+
+// returns the hash the position would have after transforming by s
+// only meaningful for symmetries that keep the board dimensions
+func (b *Board) transformedHash(s Symmetry) uint64 {
+	if s == Identity {
+		return b.koHash
+	}
+	hash := uint64(0)
+	b.black.ForEach(func(p Point) {
+		hash ^= zobristKey(b.TransformPoint(p, s), Black)
+	})
+	b.white.ForEach(func(p Point) {
+		hash ^= zobristKey(b.TransformPoint(p, s), White)
+	})
+	return hash
+}
+
+// returns the same hash for every rotation and reflection of the position,
+// the smallest of the transformed hashes (only dimension-preserving ones on rectangular boards)
+func (b *Board) CanonicalHash() uint64 {
+	canonical := b.koHash
+	for _, s := range b.sizeSymmetries()[1:] {
+		if h := b.transformedHash(s); h < canonical {
+			canonical = h
+		}
+	}
+	return canonical
+}
+
+// returns the symmetries that leave the position unchanged, always including Identity
+func (b *Board) Symmetries() []Symmetry {
+	symmetries := []Symmetry{Identity}
+	for _, s := range b.sizeSymmetries()[1:] {
+		if b.transformedHash(s) == b.koHash && b.TransformPoint(b.koPoint, s) == b.koPoint {
+			symmetries = append(symmetries, s)
+		}
+	}
+	return symmetries
+}
+
+// returns the distinct points the position's symmetries map p to where color may play, p first
+// the symmetries don't look at the history, so superko can rule out some of them
+func (b *Board) LegalOrbit(p Point, color Color) []Point {
+	orbit := []Point{p}
+	if p < 0 {
+		return orbit
+	}
+	for _, s := range b.Symmetries()[1:] {
+		if q := b.TransformPoint(p, s); !containsPoint(orbit, q) && b.IsLegal(q, color) {
+			orbit = append(orbit, q)
+		}
+	}
+	return orbit
+}
@@ -4,8 +4,8 @@ import (
 	"math/rand"
 )
 
-// zobrist hash table for position hashing
-var zobristTable [19 * 19 * 2]uint64
+// zobrist hash table for position hashing, two values per point of the largest padded grid
+var zobristTable [maxPoints * 2]uint64
 
 func init() {
 	// initialize Zobrist hash table with random values
@@ -18,32 +18,32 @@ func init() {
 // compute Zobrist hash for curr board state
 func (b *Board) computeHash() uint64 {
 	hash := uint64(0)
-	for y := 1; y <= b.size; y++ {
-		for x := 1; x <= b.size; x++ {
+	for y := 1; y <= b.height; y++ {
+		for x := 1; x <= b.width; x++ {
 			p := b.ToPoint(x, y)
 			color := b.points[p]
 			if color == Black || color == White {
-				// compute idx = (point_idx * 2) + (0 for black, 1 for white)
-				idx := (y-1)*b.size + (x - 1)
-				if color == White {
-					idx = idx*2 + 1
-				} else {
-					idx = idx * 2
-				}
-				hash ^= zobristTable[idx]
+				hash ^= zobristKey(p, color)
 			}
 		}
 	}
 	return hash
 }
 
-// check if the current position has occurred before (Ko or Superko)
-func (b *Board) isPositionRepeated() bool {
-	currentHash := b.koHash
-	for _, pastHash := range b.history {
-		if pastHash == currentHash {
-			return true
-		}
+// returns the zobrist value of a stone of the given color at p
+// points are padded grid indices, so values are consistent for boards of the same width
+func zobristKey(p Point, color Color) uint64 {
+	// compute idx = (point_idx * 2) + (0 for black, 1 for white)
+	idx := int(p) * 2
+	if color == White {
+		idx++
 	}
-	return false
+	return zobristTable[idx]
+}
+
+// check if the current position has occurred before (Ko or Superko)
+// returns the no. of the move after which it first occurred
+func (b *Board) isPositionRepeated() (int, bool) {
+	idx, repeated := b.history.find(b.koHash)
+	return idx + 1, repeated
 }
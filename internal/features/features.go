@@ -0,0 +1,198 @@
+// Package features turns positions into the float32 input planes of a neural network
+//
+// training code and in-engine inference both build their inputs here, so the two always agree
+package features
+
+import (
+	"fmt"
+
+	"github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/internal/game"
+	"github.com/awesohame/gogo/internal/selfplay"
+)
+
+// HistoryLength is the no. of earlier moves marked in the turns-since planes
+const HistoryLength = 4
+
+// indices of the planes, colours are seen from the side to move
+const (
+	PlaneOwn         = iota                            // stones of the side to move
+	PlaneOpponent                                      // stones of the other side
+	PlaneEmpty                                         // empty points
+	PlaneLiberties1                                    // stones of groups with 1 liberty
+	PlaneLiberties2                                    // stones of groups with 2 liberties
+	PlaneLiberties3                                    // stones of groups with 3 or more liberties
+	PlaneTurnsSince                                    // stone played 1 turn ago, the next HistoryLength-1 planes 2, 3, ... turns ago
+	PlaneKo          = PlaneTurnsSince + HistoryLength // point the side to move may not retake at
+	PlaneLadder      = PlaneKo + 1                     // stones lost in a ladder with the side to move playing first
+	PlaneBlackToMove = PlaneLadder + 1                 // all ones when black is to move, else zeros
+	NumPlanes        = PlaneBlackToMove + 1
+)
+
+// Planes holds the input planes of a position as one tensor of NumPlanes x Height x Width,
+// plane by plane and row by row from the top left
+type Planes struct {
+	Width  int
+	Height int
+	Data   []float32
+}
+
+// creates all zero planes
+func newPlanes(width, height int) *Planes {
+	return &Planes{Width: width, Height: height, Data: make([]float32, NumPlanes*width*height)}
+}
+
+// returns the value of a plane at 1-based (x, y)
+func (p *Planes) At(plane, x, y int) float32 {
+	return p.Data[p.offset(plane, x, y)]
+}
+
+// returns the index of 1-based (x, y) of a plane in Data
+func (p *Planes) offset(plane, x, y int) int {
+	return (plane*p.Height+y-1)*p.Width + x - 1
+}
+
+// sets a plane to 1 at 1-based (x, y)
+func (p *Planes) set(plane, x, y int) {
+	p.Data[p.offset(plane, x, y)] = 1
+}
+
+// builds the planes of the position on board with toMove to play
+// history holds the earlier positions of the game, the most recent first
+func Extract(board *engine.Board, toMove engine.Color, history []*engine.Board) *Planes {
+	return ExtractWithKo(board, toMove, history, board.KoPoint())
+}
+
+// builds the planes like Extract with the ko point given, -1 for none
+// a board keeps its ko through a pass, callers that know a pass came in between hand in -1
+func ExtractWithKo(board *engine.Board, toMove engine.Color, history []*engine.Board, ko engine.Point) *Planes {
+	return extract(board, toMove, history, koIndex(board, ko))
+}
+
+// builds the planes of the current position of a session and its last moves
+// the ko plane follows the session, which drops the ko after a pass
+func FromSession(s *game.Session) *Planes {
+	board := s.CurrentBoard()
+	return extract(board, s.CurrentTurn(), s.RecentBoards(HistoryLength), koIndex(board, s.KoPoint()))
+}
+
+// returns the index of a ko point in a plane, -1 for none
+func koIndex(board *engine.Board, p engine.Point) int {
+	if p < 0 {
+		return -1
+	}
+	x, y := board.ToXY(p)
+	return (y-1)*board.Width() + x - 1
+}
+
+// builds the planes of the i-th position of a self-play game, earlier positions give the history
+func FromSelfPlay(g selfplay.Game, i int) (*Planes, error) {
+	if i < 0 || i >= len(g.Positions) {
+		return nil, fmt.Errorf("no position %d in a game of %d", i, len(g.Positions))
+	}
+	board, err := selfPlayBoard(g, i)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]*engine.Board, 0, HistoryLength)
+	for j := i - 1; j >= 0 && len(history) < HistoryLength; j-- {
+		earlier, err := selfPlayBoard(g, j)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, earlier)
+	}
+	return extract(board, g.Positions[i].ToMove, history, g.Positions[i].KoPoint), nil
+}
+
+// sets up the board of a stored position
+func selfPlayBoard(g selfplay.Game, i int) (*engine.Board, error) {
+	board := engine.NewRectBoard(g.Width, g.Height)
+	var setup engine.Setup
+	for j, c := range g.Positions[i].Stones {
+		p := board.ToPoint(j%g.Width+1, j/g.Width+1)
+		switch c {
+		case engine.Black:
+			setup.Black = append(setup.Black, p)
+		case engine.White:
+			setup.White = append(setup.White, p)
+		}
+	}
+	return board.ApplySetup(setup)
+}
+
+// fills the planes, ko is the point index of the ko point or -1
+func extract(board *engine.Board, toMove engine.Color, history []*engine.Board, ko int) *Planes {
+	width, height := board.Width(), board.Height()
+	planes := newPlanes(width, height)
+	laddered := make(map[engine.Point]bool) // ladder status of each group, by the point read
+	for y := 1; y <= height; y++ {
+		for x := 1; x <= width; x++ {
+			p := board.ToPoint(x, y)
+			color := board.ColorAt(p)
+			if color == engine.Empty {
+				planes.set(PlaneEmpty, x, y)
+				continue
+			}
+			if color == toMove {
+				planes.set(PlaneOwn, x, y)
+			} else {
+				planes.set(PlaneOpponent, x, y)
+			}
+
+			group, _ := board.GroupAt(p)
+			libs := group.LibertyCount()
+			planes.set(PlaneLiberties1+min(libs, 3)-1, x, y)
+
+			if libs <= 2 {
+				head := group.Stones.First()
+				lost, ok := laddered[head]
+				if !ok {
+					lost = board.IsLadderCaptured(p, toMove)
+					laddered[head] = lost
+				}
+				if lost {
+					planes.set(PlaneLadder, x, y)
+				}
+			}
+		}
+	}
+
+	// a stone still standing is marked with the turn it was played, counting back from now
+	newer := board
+	for turn, older := range history {
+		if turn >= HistoryLength {
+			break
+		}
+		for y := 1; y <= height; y++ {
+			for x := 1; x <= width; x++ {
+				p := board.ToPoint(x, y)
+				if board.ColorAt(p) != engine.Empty && newer.ColorAt(p) == board.ColorAt(p) &&
+					older.ColorAt(p) == engine.Empty && !planes.marked(x, y) {
+					planes.set(PlaneTurnsSince+turn, x, y)
+				}
+			}
+		}
+		newer = older
+	}
+
+	if ko >= 0 {
+		planes.set(PlaneKo, ko%width+1, ko/width+1)
+	}
+	if toMove == engine.Black {
+		for i := 0; i < width*height; i++ {
+			planes.Data[PlaneBlackToMove*width*height+i] = 1
+		}
+	}
+	return planes
+}
+
+// reports whether a turns-since plane already marks 1-based (x, y)
+func (p *Planes) marked(x, y int) bool {
+	for turn := 0; turn < HistoryLength; turn++ {
+		if p.At(PlaneTurnsSince+turn, x, y) != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package features
+
+import "github.com/awesohame/gogo/internal/engine"
+
+// returns the symmetries that keep a width x height board's shape, all 8 on square boards
+func Symmetries(width, height int) []engine.Symmetry {
+	if width == height {
+		return engine.AllSymmetries[:]
+	}
+	return []engine.Symmetry{engine.Identity, engine.Rotate180, engine.FlipHorizontal, engine.FlipVertical}
+}
+
+// returns the planes of the position transformed by s, width and height swap for rotations
+// by 90 degrees and the diagonal reflections
+func (p *Planes) Transform(s engine.Symmetry) *Planes {
+	width, height := p.Width, p.Height
+	if s.SwapsAxes() {
+		width, height = height, width
+	}
+	t := &Planes{Width: width, Height: height, Data: make([]float32, len(p.Data))}
+	for plane := 0; plane < NumPlanes; plane++ {
+		for y := 1; y <= p.Height; y++ {
+			for x := 1; x <= p.Width; x++ {
+				tx, ty := s.TransformXY(x, y, p.Width, p.Height)
+				t.Data[t.offset(plane, tx, ty)] = p.At(plane, x, y)
+			}
+		}
+	}
+	return t
+}
+
+// returns the planes under every symmetry of Symmetries, the original first
+func Augment(p *Planes) []*Planes {
+	symmetries := Symmetries(p.Width, p.Height)
+	augmented := make([]*Planes, 0, len(symmetries))
+	for _, s := range symmetries {
+		augmented = append(augmented, p.Transform(s))
+	}
+	return augmented
+}
+
+// maps a point index (y-1)*width + (x-1) of a width x height board to the transformed board,
+// so policy targets follow their planes; width*height stands for a pass and is kept
+func TransformIndex(index, width, height int, s engine.Symmetry) int {
+	if index < 0 || index >= width*height {
+		return index
+	}
+	tx, ty := s.TransformXY(index%width+1, index/width+1, width, height)
+	if s.SwapsAxes() {
+		return (ty-1)*height + tx - 1
+	}
+	return (ty-1)*width + tx - 1
+}
@@ -0,0 +1,12 @@
+package game
+
+import "errors"
+
+// errors returned by session actions, use errors.Is to tell them apart
+// illegal moves return the errors of the engine package
+var (
+	ErrGameOver      = errors.New("game is over")
+	ErrWrongTurn     = errors.New("not your turn")
+	ErrNothingToUndo = errors.New("Cannot undo: At start of game")
+	ErrNothingToRedo = errors.New("Cannot redo: At end of history")
+)
@@ -1,63 +1,185 @@
 package game
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/awesohame/gogo/internal/engine"
 )
 
+// Rules selects how a finished game is scored
+type Rules int
+
+const (
+	ChineseRules     Rules = iota // area scoring, dead stones removed first
+	TrompTaylorRules              // area scoring, every stone on the board counts
+)
+
+// returns the name of the rule set
+func (r Rules) String() string {
+	switch r {
+	case ChineseRules:
+		return "chinese"
+	case TrompTaylorRules:
+		return "tromp-taylor"
+	default:
+		return "unknown"
+	}
+}
+
+// Config holds the settings of a new session
+type Config struct {
+	Width    int
+	Height   int
+	Komi     float64
+	Rules    Rules
+	Handicap int // no. of black stones placed before the first move, 0 or 1 for none
+}
+
 // Session manages a single game lifecycle with history for undo/redo
 type Session struct {
 	history      []*engine.Board // all board states in order
+	edits        []*editStep     // edit that led to each board state, nil for moves
 	currentIndex int             // points to curr position in history
 	currentTurn  engine.Color    // whose turn it is
 	blackPassed  bool            // true if black passed on last move
 	whitePassed  bool            // true if white passed on last move
 	gameOver     bool            // true if game has ended
-	size         int             // board size
+	width        int             // board width
+	height       int             // board height
+	komi         float64         // points added to white's score
+	rules        Rules           // scoring rules
+	handicap     int             // no. of handicap stones placed
+}
+
+// editStep remembers the side to move around a position edit, edits need not alternate turns
+type editStep struct {
+	turnBefore engine.Color
+	turnAfter  engine.Color
 }
 
 // creates a new game session with the specified board size
 func NewSession(size int) *Session {
-	initialBoard := engine.NewBoard(size)
+	return NewRectSession(size, size)
+}
+
+// creates a new game session on a width x height board
+func NewRectSession(width, height int) *Session {
+	s, err := NewSessionWithConfig(Config{Width: width, Height: height})
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// creates a new game session from a config, placing handicap stones if requested
+func NewSessionWithConfig(cfg Config) (*Session, error) {
+	if cfg.Width <= 0 {
+		cfg.Width = 9 // default board size
+	}
+	if cfg.Height <= 0 {
+		cfg.Height = cfg.Width
+	}
+	if err := engine.ValidateSize(cfg.Width, cfg.Height); err != nil {
+		return nil, err
+	}
+	if cfg.Rules != ChineseRules && cfg.Rules != TrompTaylorRules {
+		return nil, fmt.Errorf("unknown rules %d", cfg.Rules)
+	}
+
+	initialBoard := engine.NewRectBoard(cfg.Width, cfg.Height)
+	firstTurn := engine.Black // black starts first
+
+	// handicap stones go on the board before the first move, then white starts
+	points, err := initialBoard.HandicapPoints(cfg.Handicap)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range points {
+		initialBoard, err = initialBoard.ApplyMove(engine.Move{Point: p, Color: engine.Black})
+		if err != nil {
+			return nil, err
+		}
+	}
+	handicap := len(points)
+	if handicap > 0 {
+		firstTurn = engine.White
+	}
+
 	return &Session{
 		history:      []*engine.Board{initialBoard},
+		edits:        []*editStep{nil},
 		currentIndex: 0,
-		currentTurn:  engine.Black, // black starts first
+		currentTurn:  firstTurn,
 		blackPassed:  false,
 		whitePassed:  false,
 		gameOver:     false,
-		size:         size,
+		width:        initialBoard.Width(),
+		height:       initialBoard.Height(),
+		komi:         cfg.Komi,
+		rules:        cfg.Rules,
+		handicap:     handicap,
+	}, nil
+}
+
+// creates a session that starts from an existing board with the given side to move
+// size and handicap settings of the config are ignored, the board keeps its own
+func NewSessionFromBoard(board *engine.Board, toMove engine.Color, cfg Config) (*Session, error) {
+	if toMove != engine.Black && toMove != engine.White {
+		return nil, fmt.Errorf("invalid side to move %d", toMove)
+	}
+	if cfg.Rules != ChineseRules && cfg.Rules != TrompTaylorRules {
+		return nil, fmt.Errorf("unknown rules %d", cfg.Rules)
+	}
+
+	return &Session{
+		history:      []*engine.Board{board},
+		edits:        []*editStep{nil},
+		currentIndex: 0,
+		currentTurn:  toMove,
+		width:        board.Width(),
+		height:       board.Height(),
+		komi:         cfg.Komi,
+		rules:        cfg.Rules,
+	}, nil
+}
+
+// creates a session from an ASCII diagram with the given side to move, see engine.ParseDiagram
+func NewSessionFromDiagram(diagram string, toMove engine.Color) (*Session, error) {
+	board, err := engine.ParseDiagram(diagram)
+	if err != nil {
+		return nil, err
 	}
+	return NewSessionFromBoard(board, toMove, Config{})
 }
 
 // applies a move to the current board state
 // returns error if move is illegal or not the correct player's turn
 func (s *Session) MakeMove(move engine.Move) error {
+	_, err := s.MakeMoveWithResult(move)
+	return err
+}
+
+// applies a move like MakeMove and returns what it did to the board
+func (s *Session) MakeMoveWithResult(move engine.Move) (engine.MoveResult, error) {
 	if s.gameOver {
-		return errors.New("game is over")
+		return engine.MoveResult{}, ErrGameOver
 	}
 
 	// check if correct player's turn
 	if move.Color != s.currentTurn {
-		return errors.New("not your turn")
+		return engine.MoveResult{}, ErrWrongTurn
 	}
 
 	// get curr board
 	currentBoard := s.history[s.currentIndex]
 
 	// apply move using engine
-	newBoard, err := currentBoard.ApplyMove(move)
+	newBoard, result, err := currentBoard.ApplyMoveWithResult(move)
 	if err != nil {
-		return err
+		return engine.MoveResult{}, err
 	}
 
-	// truncate any future history if we're not at the end in case of undoes
-	s.history = s.history[:s.currentIndex+1]
-
-	// add new board to history
-	s.history = append(s.history, newBoard)
-	s.currentIndex++
+	s.push(newBoard, nil)
 
 	// reset pass flags since a move was made
 	s.blackPassed = false
@@ -66,13 +188,43 @@ func (s *Session) MakeMove(move engine.Move) error {
 	// switch turn
 	s.currentTurn = s.opponentColor()
 
+	return result, nil
+}
+
+// places and removes stones regardless of the rules (like SGF AB/AW/AE) and sets the side to move
+// the edit is one step of the history, so it can be undone and redone like a move
+func (s *Session) Edit(setup engine.Setup, toMove engine.Color) error {
+	if toMove != engine.Black && toMove != engine.White {
+		return fmt.Errorf("invalid side to move %d", toMove)
+	}
+
+	newBoard, err := s.history[s.currentIndex].ApplySetup(setup)
+	if err != nil {
+		return err
+	}
+	s.push(newBoard, &editStep{turnBefore: s.currentTurn, turnAfter: toMove})
+
+	// an edited position is a fresh start for passing and ending the game
+	s.gameOver = false
+	s.blackPassed = false
+	s.whitePassed = false
+	s.currentTurn = toMove
+
 	return nil
 }
 
+// adds a board state after the current one, dropping any undone states
+func (s *Session) push(board *engine.Board, edit *editStep) {
+	// truncate any future history if we're not at the end in case of undoes
+	s.history = append(s.history[:s.currentIndex+1], board)
+	s.edits = append(s.edits[:s.currentIndex+1], edit)
+	s.currentIndex++
+}
+
 // user passes turn
 func (s *Session) Pass() error {
 	if s.gameOver {
-		return errors.New("game is over")
+		return ErrGameOver
 	}
 
 	// mark that curr player passed
@@ -101,13 +253,17 @@ func (s *Session) Resign() {
 // moves game state back
 func (s *Session) Undo() error {
 	if s.currentIndex <= 0 {
-		return errors.New("Cannot undo: At start of game")
+		return ErrNothingToUndo
 	}
 
-	s.currentIndex--
+	// switch turn back, edits restore the side to move they replaced
+	if edit := s.edits[s.currentIndex]; edit != nil {
+		s.currentTurn = edit.turnBefore
+	} else {
+		s.currentTurn = s.opponentColor()
+	}
 
-	// switch turn back
-	s.currentTurn = s.opponentColor()
+	s.currentIndex--
 
 	// reset game over state if we undo from end
 	s.gameOver = false
@@ -120,13 +276,17 @@ func (s *Session) Undo() error {
 // moves game state forward
 func (s *Session) Redo() error {
 	if s.currentIndex >= len(s.history)-1 {
-		return errors.New("Cannot redo: At end of history")
+		return ErrNothingToRedo
 	}
 
 	s.currentIndex++
 
 	// switch turn forward
-	s.currentTurn = s.opponentColor()
+	if edit := s.edits[s.currentIndex]; edit != nil {
+		s.currentTurn = edit.turnAfter
+	} else {
+		s.currentTurn = s.opponentColor()
+	}
 
 	return nil
 }
@@ -136,6 +296,29 @@ func (s *Session) CurrentBoard() *engine.Board {
 	return s.history[s.currentIndex]
 }
 
+// returns up to n board states before the current one, the most recent first
+// passes leave the board unchanged and have no state of their own
+func (s *Session) RecentBoards(n int) []*engine.Board {
+	boards := make([]*engine.Board, 0, min(n, s.currentIndex))
+	for i := s.currentIndex - 1; i >= 0 && len(boards) < n; i-- {
+		boards = append(boards, s.history[i])
+	}
+	return boards
+}
+
+// returns the point the side to move may not take back because of ko, -1 if there is none
+// a pass lifts the ban, after it the player who took the ko is to move again
+func (s *Session) KoPoint() engine.Point {
+	passed := s.whitePassed
+	if s.currentTurn == engine.White {
+		passed = s.blackPassed
+	}
+	if passed {
+		return -1
+	}
+	return s.CurrentBoard().KoPoint()
+}
+
 // returns whose turn it is
 func (s *Session) CurrentTurn() engine.Color {
 	return s.currentTurn
@@ -146,17 +329,40 @@ func (s *Session) IsGameOver() bool {
 	return s.gameOver
 }
 
-// calcs and returns the curr score
+// calcs and returns the curr score under the session rules
 func (s *Session) GetScore() engine.Score {
+	if s.rules == TrompTaylorRules {
+		return s.CurrentBoard().CalculateTrompTaylorScore()
+	}
 	return s.CurrentBoard().CalculateChineseScore()
 }
 
 // calcs score with komi
 func (s *Session) GetScoreWithKomi(komi float64) (black float64, white float64, winner engine.Color) {
-	return s.CurrentBoard().CalculateScoreWithKomi(komi)
+	return s.GetScore().WithKomi(komi)
+}
+
+// calcs score with the session komi
+func (s *Session) GetFinalScore() (black float64, white float64, winner engine.Color) {
+	return s.GetScoreWithKomi(s.komi)
 }
 
-// returns the no. of moves made in the game
+// returns the komi added to white's score
+func (s *Session) Komi() float64 {
+	return s.komi
+}
+
+// returns the scoring rules
+func (s *Session) Rules() Rules {
+	return s.rules
+}
+
+// returns the no. of handicap stones placed at the start
+func (s *Session) Handicap() int {
+	return s.handicap
+}
+
+// returns the no. of moves made in the game, position edits count as one each
 func (s *Session) MoveCount() int {
 	return s.currentIndex
 }
@@ -179,7 +385,17 @@ func (s *Session) opponentColor() engine.Color {
 	return engine.Black
 }
 
-// returns board size
+// returns board size, the width on rectangular boards
 func (s *Session) Size() int {
-	return s.size
+	return s.width
+}
+
+// returns board width
+func (s *Session) Width() int {
+	return s.width
+}
+
+// returns board height
+func (s *Session) Height() int {
+	return s.height
 }
@@ -0,0 +1,145 @@
+package nn
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/awesohame/gogo/internal/features"
+)
+
+// Weight files start with a 17 byte header, all numbers little endian:
+//
+//	"GGNN", the version byte 1, then u16 width, height, input planes, filters, blocks and
+//	value hidden units
+//
+// followed by the f32 values of every tensor in this order, weights before biases:
+//
+//	input conv     filters x planes x 3 x 3, filters
+//	each block     filters x filters x 3 x 3, filters, then the second conv alike
+//	policy conv    2 x filters (1x1), 2
+//	policy dense   (points+1) x (2*points), points+1
+//	value conv     1 x filters (1x1), 1
+//	value dense 1  hidden x points, hidden
+//	value dense 2  1 x hidden, 1
+//
+// convolutions are indexed [out][in][ky][kx], dense layers [out][in], and the flattened input
+// of a dense layer is channel by channel, row by row from the top left. points is width*height
+// and the last policy output is the pass. batch norm must be folded into the conv weights and
+// biases first. the input planes must equal features.NumPlanes of the engine loading the file
+
+// magic bytes and version at the start of every weight file
+const (
+	weightsMagic   = "GGNN"
+	weightsVersion = 1
+)
+
+// ErrBadWeights is returned when loading something that is not a weight file of this engine
+var ErrBadWeights = errors.New("not a network weight file")
+
+// a tensor in file order, fanIn is 0 for biases
+type tensor struct {
+	data  []float32
+	fanIn int
+}
+
+// returns every tensor of the network in file order
+func (n *Network) tensors() []tensor {
+	list := make([]tensor, 0, 4*len(n.blocks)+12)
+	addConv := func(c conv) {
+		list = append(list, tensor{c.weights, c.in * c.size * c.size}, tensor{c.bias, 0})
+	}
+	addDense := func(d dense) {
+		list = append(list, tensor{d.weights, d.in}, tensor{d.bias, 0})
+	}
+	addConv(n.input)
+	for _, block := range n.blocks {
+		addConv(block[0])
+		addConv(block[1])
+	}
+	addConv(n.policyConv)
+	addDense(n.policyFC)
+	addConv(n.valueConv)
+	addDense(n.valueFC1)
+	addDense(n.valueFC2)
+	return list
+}
+
+// reads a network from a weight file
+func Load(r io.Reader) (*Network, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(weightsMagic)+1+6*2)
+	if _, err := io.ReadFull(br, header); err != nil || string(header[:len(weightsMagic)]) != weightsMagic {
+		return nil, ErrBadWeights
+	}
+	if v := header[len(weightsMagic)]; v != weightsVersion {
+		return nil, fmt.Errorf("%w: unknown version %d", ErrBadWeights, v)
+	}
+	field := func(i int) int {
+		return int(binary.LittleEndian.Uint16(header[len(weightsMagic)+1+2*i:]))
+	}
+	if planes := field(2); planes != features.NumPlanes {
+		return nil, fmt.Errorf("%w: made for %d input planes, the engine has %d", ErrBadWeights, planes, features.NumPlanes)
+	}
+	cfg := Config{Width: field(0), Height: field(1), Filters: field(3), Blocks: field(4), ValueHidden: field(5)}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadWeights, err)
+	}
+
+	n := newNetwork(cfg)
+	buf := make([]byte, 4)
+	for _, t := range n.tensors() {
+		for i := range t.data {
+			if _, err := io.ReadFull(br, buf); err != nil {
+				return nil, fmt.Errorf("%w: file ends early", ErrBadWeights)
+			}
+			t.data[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf))
+		}
+	}
+	if _, err := br.ReadByte(); err != io.EOF {
+		return nil, fmt.Errorf("%w: data after the last tensor", ErrBadWeights)
+	}
+	return n, nil
+}
+
+// reads a network from a weight file on disk
+func LoadFile(path string) (*Network, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	n, err := Load(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return n, nil
+}
+
+// writes the network as a weight file
+func (n *Network) Save(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	header := []byte(weightsMagic)
+	header = append(header, weightsVersion)
+	for _, v := range []int{n.cfg.Width, n.cfg.Height, features.NumPlanes, n.cfg.Filters, n.cfg.Blocks, n.cfg.ValueHidden} {
+		header = binary.LittleEndian.AppendUint16(header, uint16(v))
+	}
+	if _, err := bw.Write(header); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 4)
+	for _, t := range n.tensors() {
+		for _, v := range t.data {
+			binary.LittleEndian.PutUint32(buf, math.Float32bits(v))
+			if _, err := bw.Write(buf); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,237 @@
+// Package nn runs small residual convolutional policy/value networks on the CPU in pure Go
+//
+// the input is the planes of package features. a 3x3 convolution widens them to Filters
+// channels, followed by Blocks residual blocks of two 3x3 convolutions each. the policy head
+// is a 1x1 convolution to 2 channels and a dense layer to one logit per point and the pass,
+// the value head a 1x1 convolution to 1 channel, a dense layer of ValueHidden units and a
+// dense layer to a single tanh output. batch norm must be folded into the convolutions
+// before export, see format.go for the weight file
+package nn
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/internal/features"
+)
+
+// Config is the shape of a network
+type Config struct {
+	Width       int // board width the network is made for
+	Height      int // board height the network is made for
+	Filters     int // channels of the residual trunk
+	Blocks      int // no. of residual blocks
+	ValueHidden int // units of the hidden layer of the value head
+}
+
+// Network is a policy/value network, safe for concurrent use
+type Network struct {
+	cfg        Config
+	input      conv
+	blocks     [][2]conv
+	policyConv conv
+	policyFC   dense
+	valueConv  conv
+	valueFC1   dense
+	valueFC2   dense
+}
+
+// convolution with zero padding that keeps the board size
+type conv struct {
+	in, out, size int
+	weights       []float32 // out x in x size x size
+	bias          []float32 // out
+}
+
+// fully connected layer
+type dense struct {
+	in, out int
+	weights []float32 // out x in
+	bias    []float32 // out
+}
+
+// creates a network with every weight and bias zero, to be filled by a loader
+func newNetwork(cfg Config) *Network {
+	points := cfg.Width * cfg.Height
+	n := &Network{
+		cfg:        cfg,
+		input:      newConv(features.NumPlanes, cfg.Filters, 3),
+		blocks:     make([][2]conv, cfg.Blocks),
+		policyConv: newConv(cfg.Filters, 2, 1),
+		policyFC:   newDense(2*points, points+1),
+		valueConv:  newConv(cfg.Filters, 1, 1),
+		valueFC1:   newDense(points, cfg.ValueHidden),
+		valueFC2:   newDense(cfg.ValueHidden, 1),
+	}
+	for i := range n.blocks {
+		n.blocks[i] = [2]conv{newConv(cfg.Filters, cfg.Filters, 3), newConv(cfg.Filters, cfg.Filters, 3)}
+	}
+	return n
+}
+
+func newConv(in, out, size int) conv {
+	return conv{in: in, out: out, size: size,
+		weights: make([]float32, out*in*size*size), bias: make([]float32, out)}
+}
+
+func newDense(in, out int) dense {
+	return dense{in: in, out: out, weights: make([]float32, out*in), bias: make([]float32, out)}
+}
+
+// creates a network with random He-initialised weights and zero biases, a starting point for training
+func NewNetwork(cfg Config, seed int64) (*Network, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	n := newNetwork(cfg)
+	rng := rand.New(rand.NewSource(seed))
+	for _, t := range n.tensors() {
+		if t.fanIn == 0 {
+			continue // biases
+		}
+		std := math.Sqrt(2 / float64(t.fanIn))
+		for i := range t.data {
+			t.data[i] = float32(rng.NormFloat64() * std)
+		}
+	}
+	return n, nil
+}
+
+// reports a shape the format or the engine cannot hold
+func (cfg Config) validate() error {
+	if err := engine.ValidateSize(cfg.Width, cfg.Height); err != nil {
+		return err
+	}
+	if cfg.Filters < 1 || cfg.Blocks < 0 || cfg.ValueHidden < 1 ||
+		cfg.Filters > math.MaxUint16 || cfg.Blocks > math.MaxUint16 || cfg.ValueHidden > math.MaxUint16 {
+		return fmt.Errorf("bad network shape: %d filters, %d blocks, %d value units", cfg.Filters, cfg.Blocks, cfg.ValueHidden)
+	}
+	return nil
+}
+
+// returns the shape of the network
+func (n *Network) Config() Config {
+	return n.cfg
+}
+
+// runs the network on a position and returns the move probabilities, one per point row by
+// row from the top left and the pass last, and the expected result in [-1, 1] for the side to move
+func (n *Network) Forward(planes *features.Planes) ([]float32, float32, error) {
+	if planes.Width != n.cfg.Width || planes.Height != n.cfg.Height {
+		return nil, 0, fmt.Errorf("network is for %dx%d boards, got %dx%d",
+			n.cfg.Width, n.cfg.Height, planes.Width, planes.Height)
+	}
+	width, height := n.cfg.Width, n.cfg.Height
+	if len(planes.Data) != features.NumPlanes*width*height {
+		return nil, 0, fmt.Errorf("expected %d input values, got %d", features.NumPlanes*width*height, len(planes.Data))
+	}
+
+	x := n.input.forward(planes.Data, width, height)
+	relu(x)
+	for _, block := range n.blocks {
+		h := block[0].forward(x, width, height)
+		relu(h)
+		h = block[1].forward(h, width, height)
+		for i := range h {
+			h[i] += x[i]
+		}
+		relu(h)
+		x = h
+	}
+
+	p := n.policyConv.forward(x, width, height)
+	relu(p)
+	policy := n.policyFC.forward(p)
+	softmax(policy)
+
+	v := n.valueConv.forward(x, width, height)
+	relu(v)
+	hidden := n.valueFC1.forward(v)
+	relu(hidden)
+	value := float32(math.Tanh(float64(n.valueFC2.forward(hidden)[0])))
+	return policy, value, nil
+}
+
+// implements ai.PolicyValueNet, history holds earlier positions, the most recent first
+func (n *Network) PolicyValue(board *engine.Board, toMove engine.Color, history []*engine.Board, ko engine.Point) ([]float32, float32, error) {
+	return n.Forward(features.ExtractWithKo(board, toMove, history, ko))
+}
+
+// applies the convolution to in, channels of width x height each
+func (c *conv) forward(in []float32, width, height int) []float32 {
+	area := width * height
+	out := make([]float32, c.out*area)
+	pad := c.size / 2
+	for o := 0; o < c.out; o++ {
+		dst := out[o*area : (o+1)*area]
+		for i := range dst {
+			dst[i] = c.bias[o]
+		}
+		for i := 0; i < c.in; i++ {
+			src := in[i*area : (i+1)*area]
+			kernel := c.weights[(o*c.in+i)*c.size*c.size:]
+			for ky := 0; ky < c.size; ky++ {
+				dy := ky - pad
+				y0, y1 := max(0, -dy), min(height, height-dy)
+				for kx := 0; kx < c.size; kx++ {
+					w := kernel[ky*c.size+kx]
+					if w == 0 {
+						continue
+					}
+					dx := kx - pad
+					x0, x1 := max(0, -dx), min(width, width-dx)
+					for y := y0; y < y1; y++ {
+						row := dst[y*width : (y+1)*width]
+						srcRow := src[(y+dy)*width : (y+dy+1)*width]
+						for x := x0; x < x1; x++ {
+							row[x] += w * srcRow[x+dx]
+						}
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+// applies the layer to in
+func (d *dense) forward(in []float32) []float32 {
+	out := make([]float32, d.out)
+	for o := range out {
+		sum := d.bias[o]
+		row := d.weights[o*d.in : (o+1)*d.in]
+		for i, v := range in {
+			sum += row[i] * v
+		}
+		out[o] = sum
+	}
+	return out
+}
+
+// sets negative values to 0 in place
+func relu(x []float32) {
+	for i, v := range x {
+		if v < 0 {
+			x[i] = 0
+		}
+	}
+}
+
+// turns logits into probabilities in place
+func softmax(x []float32) {
+	top := x[0]
+	for _, v := range x {
+		top = max(top, v)
+	}
+	sum := 0.0
+	for i, v := range x {
+		e := math.Exp(float64(v - top))
+		x[i] = float32(e)
+		sum += e
+	}
+	for i := range x {
+		x[i] = float32(float64(x[i]) / sum)
+	}
+}
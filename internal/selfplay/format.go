@@ -0,0 +1,238 @@
+package selfplay
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/awesohame/gogo/internal/engine"
+)
+
+// Self-play files hold any no. of games after a 5 byte header, all numbers little endian:
+//
+//	header    "GGSP" and the version byte 1
+//	game      u8 width, u8 height, f32 komi, i8 winner (1 black, -1 white, 0 draw),
+//	          f32 score (black minus white with komi), u16 no. of positions, then the positions
+//	position  u8 side to move (1 black, 2 white)
+//	          stones, 2 bits per point (0 empty, 1 black, 2 white), point i in bits 2*(i%4)
+//	          of byte i/4, ceil(width*height/4) bytes
+//	          u16 ko point, 0xffff for none
+//	          u16 move played
+//	          u16 no. of visited moves, then u16 move and u32 visits for each
+//
+// points are indexed row by row from the top left, (y-1)*width + (x-1), and the index
+// width*height stands for a pass. the target of the value head is the winner seen from
+// the side to move, the policy target the visits divided by their sum
+
+// magic bytes and version at the start of every file
+const (
+	fileMagic   = "GGSP"
+	fileVersion = 1
+)
+
+// ko point of a position without one
+const noKo = 0xffff
+
+// ErrBadFormat is returned when reading something that is not a self-play file
+var ErrBadFormat = errors.New("not a self-play file")
+
+// Game is a self-play game as stored on disk
+type Game struct {
+	Width     int
+	Height    int
+	Komi      float64
+	Winner    engine.Color // Empty for a draw
+	Score     float64      // black minus white with komi
+	Positions []Position
+}
+
+// Position is a searched position of a game
+type Position struct {
+	ToMove  engine.Color
+	Stones  []engine.Color // row by row from the top left
+	KoPoint int            // index of the point the side to move may not retake at, -1 for none
+	Move    int            // index of the move played, Width*Height for a pass
+	Visits  []Visit        // root visits of the search, moves never visited are left out
+}
+
+// Visit is the no. of times the search visited a root move
+type Visit struct {
+	Move  int // point index, Width*Height for a pass
+	Count int
+}
+
+// Writer writes games to a self-play file
+type Writer struct {
+	w *bufio.Writer
+}
+
+// creates a writer and writes the file header
+func NewWriter(w io.Writer) (*Writer, error) {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(fileMagic); err != nil {
+		return nil, err
+	}
+	if err := bw.WriteByte(fileVersion); err != nil {
+		return nil, err
+	}
+	return &Writer{w: bw}, nil
+}
+
+// creates a writer adding games to a file that already has its header
+func AppendWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// appends a game
+func (w *Writer) WriteGame(g Game) error {
+	points := g.Width * g.Height
+	if g.Width < 1 || g.Width > 255 || g.Height < 1 || g.Height > 255 || points >= noKo {
+		return fmt.Errorf("cannot store a %dx%d board", g.Width, g.Height)
+	}
+	if len(g.Positions) > math.MaxUint16 {
+		return fmt.Errorf("cannot store %d positions in one game", len(g.Positions))
+	}
+
+	buf := []byte{byte(g.Width), byte(g.Height)}
+	buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(g.Komi)))
+	buf = append(buf, byte(winnerCode(g.Winner)))
+	buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(g.Score)))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(len(g.Positions)))
+
+	for _, p := range g.Positions {
+		if len(p.Stones) != points {
+			return fmt.Errorf("position has %d points, the board %d", len(p.Stones), points)
+		}
+		buf = append(buf, byte(p.ToMove))
+
+		packed := make([]byte, (points+3)/4)
+		for i, c := range p.Stones {
+			packed[i/4] |= byte(c&3) << (2 * (i % 4))
+		}
+		buf = append(buf, packed...)
+
+		ko := uint16(noKo)
+		if p.KoPoint >= 0 {
+			ko = uint16(p.KoPoint)
+		}
+		buf = binary.LittleEndian.AppendUint16(buf, ko)
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(p.Move))
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(len(p.Visits)))
+		for _, v := range p.Visits {
+			buf = binary.LittleEndian.AppendUint16(buf, uint16(v.Move))
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(v.Count))
+		}
+	}
+
+	_, err := w.w.Write(buf)
+	return err
+}
+
+// writes out buffered games
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Reader reads games from a self-play file
+type Reader struct {
+	r *bufio.Reader
+}
+
+// creates a reader and checks the file header
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(fileMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil || string(header[:len(fileMagic)]) != fileMagic {
+		return nil, ErrBadFormat
+	}
+	if header[len(fileMagic)] != fileVersion {
+		return nil, fmt.Errorf("%w: unknown version %d", ErrBadFormat, header[len(fileMagic)])
+	}
+	return &Reader{r: br}, nil
+}
+
+// reads the next game, io.EOF after the last one
+func (r *Reader) ReadGame() (Game, error) {
+	var head [13]byte
+	if _, err := io.ReadFull(r.r, head[:]); err != nil {
+		if err == io.EOF {
+			return Game{}, io.EOF
+		}
+		return Game{}, truncated(err)
+	}
+	g := Game{
+		Width:  int(head[0]),
+		Height: int(head[1]),
+		Komi:   float64(math.Float32frombits(binary.LittleEndian.Uint32(head[2:]))),
+		Winner: winnerColor(int8(head[6])),
+		Score:  float64(math.Float32frombits(binary.LittleEndian.Uint32(head[7:]))),
+	}
+	points := g.Width * g.Height
+	g.Positions = make([]Position, binary.LittleEndian.Uint16(head[11:]))
+
+	fixed := make([]byte, 1+(points+3)/4+6)
+	for i := range g.Positions {
+		if _, err := io.ReadFull(r.r, fixed); err != nil {
+			return Game{}, truncated(err)
+		}
+		p := Position{ToMove: engine.Color(fixed[0]), Stones: make([]engine.Color, points)}
+		for j := range p.Stones {
+			p.Stones[j] = engine.Color(fixed[1+j/4] >> (2 * (j % 4)) & 3)
+		}
+		rest := fixed[1+(points+3)/4:]
+		p.KoPoint = -1
+		if ko := binary.LittleEndian.Uint16(rest); ko != noKo {
+			p.KoPoint = int(ko)
+		}
+		p.Move = int(binary.LittleEndian.Uint16(rest[2:]))
+
+		visits := make([]byte, 6*int(binary.LittleEndian.Uint16(rest[4:])))
+		if _, err := io.ReadFull(r.r, visits); err != nil {
+			return Game{}, truncated(err)
+		}
+		p.Visits = make([]Visit, len(visits)/6)
+		for j := range p.Visits {
+			p.Visits[j] = Visit{
+				Move:  int(binary.LittleEndian.Uint16(visits[6*j:])),
+				Count: int(binary.LittleEndian.Uint32(visits[6*j+2:])),
+			}
+		}
+		g.Positions[i] = p
+	}
+	return g, nil
+}
+
+// reports a game cut off in the middle
+func truncated(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: truncated game", ErrBadFormat)
+	}
+	return err
+}
+
+// returns the stored code of a winner
+func winnerCode(c engine.Color) int8 {
+	switch c {
+	case engine.Black:
+		return 1
+	case engine.White:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// returns the winner of a stored code
+func winnerColor(code int8) engine.Color {
+	switch code {
+	case 1:
+		return engine.Black
+	case -1:
+		return engine.White
+	default:
+		return engine.Empty
+	}
+}
@@ -0,0 +1,214 @@
+// Package selfplay generates training data from games of MCTSBot against itself
+package selfplay
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+
+	"github.com/awesohame/gogo/internal/ai"
+	"github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/internal/game"
+)
+
+// Config holds the settings of a self-play run
+type Config struct {
+	Width            int     // board width, 9 if 0
+	Height           int     // board height, Width if 0
+	Komi             float64 // points added to white's score
+	Rules            game.Rules
+	Games            int     // no. of games to play
+	Simulations      int     // MCTS simulations per move
+	OpeningMoves     int     // each game starts with up to this many random moves, which are not recorded
+	Temperature      float64 // moves are drawn with probability visits^(1/Temperature), 0 plays the most visited
+	TemperatureMoves int     // recorded moves drawn with the temperature, after that the most visited is played
+	MaxMoves         int     // moves before a game is stopped and scored as it stands (if 0, 3 per point)
+	Concurrency      int     // games played at once, 1 if 0
+	Seed             int64   // seeds the openings and move draws, game i uses Seed+i
+}
+
+// plays cfg.Games games and hands each to emit, one call at a time in the order they finish
+// stops at the first error returned by emit
+func Generate(cfg Config, emit func(Game) error) error {
+	if cfg.Width <= 0 {
+		cfg.Width = 9
+	}
+	if cfg.Height <= 0 {
+		cfg.Height = cfg.Width
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.MaxMoves <= 0 {
+		cfg.MaxMoves = 3 * cfg.Width * cfg.Height
+	}
+	if _, err := newSession(cfg); err != nil {
+		return err
+	}
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	indices := make(chan int)
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indices {
+				mu.Lock()
+				failed := firstErr != nil
+				mu.Unlock()
+				if failed {
+					continue
+				}
+
+				g := playGame(cfg, rand.New(rand.NewSource(cfg.Seed+int64(index))))
+
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = emit(g)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := 0; i < cfg.Games; i++ {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+	return firstErr
+}
+
+// plays one game, recording every position searched after the opening
+func playGame(cfg Config, rng *rand.Rand) Game {
+	session, err := newSession(cfg)
+	if err != nil {
+		panic(err) // the config was checked by Generate
+	}
+	bot := ai.NewMCTSBot(cfg.Simulations)
+	bot.ReuseTree = false // both sides share the bot, the last tree is never the opponent's
+	bot.Verbose = false
+
+	g := Game{Width: cfg.Width, Height: cfg.Height, Komi: cfg.Komi}
+	opening := ai.NewRandomBot(rng.Int63())
+	openingMoves := 0
+	if cfg.OpeningMoves > 0 {
+		openingMoves = rng.Intn(cfg.OpeningMoves + 1)
+	}
+
+	for moves := 0; moves < cfg.MaxMoves && !session.IsGameOver(); moves++ {
+		board := session.CurrentBoard()
+		color := session.CurrentTurn()
+
+		var move engine.Move
+		if moves < openingMoves {
+			move = opening.SelectMove(board, color)
+		} else {
+			result := bot.Search(board, color)
+			position := newPosition(board, color, session.KoPoint(), result.Candidates)
+			move = result.Move
+			if len(g.Positions) < cfg.TemperatureMoves && cfg.Temperature > 0 {
+				move = drawMove(result, cfg.Temperature, rng, move)
+			}
+			// symmetric moves were searched as one, play any legal member of the orbit alike
+			orbit := board.LegalOrbit(move.Point, color)
+			move.Point = orbit[rng.Intn(len(orbit))]
+			position.Move = pointIndex(board, move.Point)
+			g.Positions = append(g.Positions, position)
+		}
+
+		if move.Point < 0 {
+			session.Pass()
+		} else if err := session.MakeMove(move); err != nil {
+			break // the bots only play legal moves, score the game as it stands if not
+		}
+	}
+
+	blackScore, whiteScore, winner := session.GetFinalScore()
+	g.Winner = winner
+	g.Score = blackScore - whiteScore
+	return g
+}
+
+// records the board, its ko point and the root visits of a searched position
+// symmetric root moves are searched as one, their visits are shared out over the legal orbit
+func newPosition(board *engine.Board, color engine.Color, ko engine.Point, candidates []ai.Candidate) Position {
+	width, height := board.Width(), board.Height()
+	p := Position{
+		ToMove:  color,
+		Stones:  make([]engine.Color, 0, width*height),
+		KoPoint: -1,
+		Move:    width * height,
+	}
+	for y := 1; y <= height; y++ {
+		for x := 1; x <= width; x++ {
+			p.Stones = append(p.Stones, board.At(x, y))
+		}
+	}
+	if ko >= 0 {
+		p.KoPoint = pointIndex(board, ko)
+	}
+
+	for _, c := range candidates {
+		if c.Visits == 0 {
+			continue
+		}
+		orbit := board.LegalOrbit(c.Move.Point, color)
+		for i, q := range orbit {
+			share := c.Visits / len(orbit)
+			if i < c.Visits%len(orbit) {
+				share++
+			}
+			if share > 0 {
+				p.Visits = append(p.Visits, Visit{Move: pointIndex(board, q), Count: share})
+			}
+		}
+	}
+	if len(p.Visits) == 0 {
+		// nothing worth searching, the bot passes
+		p.Visits = []Visit{{Move: width * height, Count: 1}}
+	}
+	return p
+}
+
+// draws a root move with probability visits^(1/temperature), fallback when nothing was visited
+func drawMove(result ai.SearchResult, temperature float64, rng *rand.Rand, fallback engine.Move) engine.Move {
+	weights := make([]float64, len(result.Candidates))
+	total := 0.0
+	for i, c := range result.Candidates {
+		weights[i] = math.Pow(float64(c.Visits), 1/temperature)
+		total += weights[i]
+	}
+	if total == 0 {
+		return fallback
+	}
+	r := rng.Float64() * total
+	for i, w := range weights {
+		if r -= w; r < 0 {
+			return result.Candidates[i].Move
+		}
+	}
+	return fallback
+}
+
+// returns the file index of a point, width*height for a pass
+func pointIndex(board *engine.Board, p engine.Point) int {
+	if p < 0 {
+		return board.Width() * board.Height()
+	}
+	x, y := board.ToXY(p)
+	return (y-1)*board.Width() + (x - 1)
+}
+
+// creates the session of a game
+func newSession(cfg Config) (*game.Session, error) {
+	return game.NewSessionWithConfig(game.Config{
+		Width:  cfg.Width,
+		Height: cfg.Height,
+		Komi:   cfg.Komi,
+		Rules:  cfg.Rules,
+	})
+}
@@ -0,0 +1,170 @@
+// Package bot provides computer opponents and analysis for games created with pkg/engine
+package bot
+
+import (
+	"time"
+
+	"github.com/awesohame/gogo/internal/ai"
+	"github.com/awesohame/gogo/internal/bridge"
+	eng "github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/pkg/engine"
+)
+
+// ErrGameOver is returned when asking for a move in a finished game
+var ErrGameOver = engine.ErrGameOver
+
+// Strength is a preset search budget
+type Strength int
+
+const (
+	Beginner Strength = iota
+	Easy
+	Medium
+	Hard
+	Expert
+)
+
+// no. of simulations per move for each preset
+var strengthSimulations = map[Strength]int{
+	Beginner: 50,
+	Easy:     200,
+	Medium:   800,
+	Hard:     3000,
+	Expert:   10000,
+}
+
+// returns the name of the preset
+func (s Strength) String() string {
+	switch s {
+	case Beginner:
+		return "beginner"
+	case Easy:
+		return "easy"
+	case Medium:
+		return "medium"
+	case Hard:
+		return "hard"
+	case Expert:
+		return "expert"
+	default:
+		return "unknown"
+	}
+}
+
+// Option tunes the search of a bot
+type Option func(*ai.MCTSBot)
+
+// sets the no. of simulations per move, overriding the preset
+func WithSimulations(n int) Option {
+	return func(b *ai.MCTSBot) {
+		b.MaxSimulations = n
+	}
+}
+
+// searches for a fixed time per move instead of a fixed no. of simulations
+func WithTimeLimit(d time.Duration) Option {
+	return func(b *ai.MCTSBot) {
+		b.TimeLimit = d.Seconds()
+	}
+}
+
+// sets the UCB exploration constant (sqrt 2 by default)
+func WithExploration(c float64) Option {
+	return func(b *ai.MCTSBot) {
+		b.ExplorationC = c
+	}
+}
+
+// keeps the search tree between moves (on by default)
+func WithTreeReuse(reuse bool) Option {
+	return func(b *ai.MCTSBot) {
+		b.ReuseTree = reuse
+	}
+}
+
+// Bot is an MCTS computer player, not safe for concurrent use
+type Bot struct {
+	mcts *ai.MCTSBot
+}
+
+// Candidate holds the search stats of a possible move
+type Candidate struct {
+	Move    engine.Move
+	Visits  int
+	WinRate float64 // for the player making the move
+}
+
+// Analysis is the result of searching a position
+type Analysis struct {
+	Best        engine.Move
+	Simulations int
+	Candidates  []Candidate // most visited first
+}
+
+// creates a bot with a strength preset, adjusted by options
+func New(strength Strength, opts ...Option) *Bot {
+	simulations, ok := strengthSimulations[strength]
+	if !ok {
+		simulations = strengthSimulations[Medium]
+	}
+
+	mcts := ai.NewMCTSBot(simulations)
+	mcts.Verbose = false
+	for _, opt := range opts {
+		opt(mcts)
+	}
+	return &Bot{mcts: mcts}
+}
+
+// searches the current position of a game and returns the move for the player to move
+func (b *Bot) SelectMove(g *engine.Game) (engine.Move, error) {
+	analysis, err := b.Analyze(g)
+	if err != nil {
+		return engine.Move{}, err
+	}
+	return analysis.Best, nil
+}
+
+// selects a move and plays it in the game
+func (b *Bot) Play(g *engine.Game) (engine.Move, error) {
+	move, err := b.SelectMove(g)
+	if err != nil {
+		return move, err
+	}
+	return move, g.MakeMove(move)
+}
+
+// searches the current position of a game and returns the stats of the candidate moves
+func (b *Bot) Analyze(g *engine.Game) (Analysis, error) {
+	if g.IsGameOver() {
+		return Analysis{}, ErrGameOver
+	}
+
+	session := bridge.GameSession(g)
+	board := session.CurrentBoard()
+	color := session.CurrentTurn()
+	result := b.mcts.Search(board, color)
+
+	analysis := Analysis{
+		Best:        toPublicMove(board, result.Move),
+		Simulations: result.Simulations,
+		Candidates:  make([]Candidate, 0, len(result.Candidates)),
+	}
+	for _, c := range result.Candidates {
+		analysis.Candidates = append(analysis.Candidates, Candidate{
+			Move:    toPublicMove(board, c.Move),
+			Visits:  c.Visits,
+			WinRate: c.WinRate,
+		})
+	}
+	return analysis, nil
+}
+
+// converts an engine move to a public move, negative points are passes
+func toPublicMove(board *eng.Board, m eng.Move) engine.Move {
+	if m.Point < 0 {
+		return engine.PassMove(m.Color)
+	}
+	x, y := board.ToXY(m.Point)
+	return engine.Move{Point: engine.Point{X: x, Y: y}, Color: m.Color}
+}
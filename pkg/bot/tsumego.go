@@ -0,0 +1,122 @@
+package bot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/awesohame/gogo/internal/ai"
+	"github.com/awesohame/gogo/internal/bridge"
+	eng "github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/pkg/engine"
+)
+
+// LifeStatus is the fate of the target group of a life-and-death problem
+type LifeStatus = ai.TsumegoStatus
+
+const (
+	StatusUnknown = ai.TsumegoUnknown // the budget ran out first
+	Lives         = ai.TsumegoLives   // the group cannot be captured, seki included
+	Dies          = ai.TsumegoDies    // the attacker captures the group
+)
+
+// SolverOption tunes the life-and-death search
+type SolverOption func(*ai.TsumegoSolver)
+
+// sets the no. of positions to visit before giving up (a million by default, 0 for no limit)
+func WithNodeLimit(n int) SolverOption {
+	return func(s *ai.TsumegoSolver) {
+		s.MaxNodes = n
+	}
+}
+
+// gives up after d
+func WithSolveTime(d time.Duration) SolverOption {
+	return func(s *ai.TsumegoSolver) {
+		s.TimeLimit = d.Seconds()
+	}
+}
+
+// sets how many plies of every losing try the refutation tree shows (4 by default)
+func WithTreeDepth(plies int) SolverOption {
+	return func(s *ai.TsumegoSolver) {
+		s.TreeDepth = plies
+	}
+}
+
+// Solution is the answer to a life-and-death problem
+type Solution struct {
+	Status  LifeStatus   // fate of the target with the side to move playing first
+	Success bool         // whether the side to move kills (as attacker) or lives (as defender)
+	KeyMove engine.Move  // winning first move when Success, a pass when any move will do
+	Tree    []*Variation // first moves: the key move when Success, else every try with its refutation
+	Nodes   int          // positions visited
+}
+
+// Variation is a move of the refutation tree with the replies worth showing
+type Variation struct {
+	Move    engine.Move
+	Replies []*Variation
+}
+
+// reads out whether the group at target lives, with the player to move in the game playing first
+// both sides only play at the points of region, nil means the whole board, so keep it to the
+// problem's eye space and surroundings
+func SolveLifeAndDeath(g *engine.Game, target engine.Point, region []engine.Point, opts ...SolverOption) (Solution, error) {
+	session := bridge.GameSession(g)
+	board := session.CurrentBoard()
+
+	toPoint := func(p engine.Point) (eng.Point, error) {
+		if p.X < 1 || p.X > board.Width() || p.Y < 1 || p.Y > board.Height() {
+			return 0, fmt.Errorf("%w: %s", engine.ErrOffBoard, p)
+		}
+		return board.ToPoint(p.X, p.Y), nil
+	}
+
+	t, err := toPoint(target)
+	if err != nil {
+		return Solution{}, err
+	}
+	var points []eng.Point
+	if region != nil {
+		points = make([]eng.Point, 0, len(region))
+		for _, p := range region {
+			point, err := toPoint(p)
+			if err != nil {
+				return Solution{}, err
+			}
+			points = append(points, point)
+		}
+	}
+
+	solver := ai.NewTsumegoSolver()
+	for _, opt := range opts {
+		opt(solver)
+	}
+	result, err := solver.Solve(board, t, points, session.CurrentTurn())
+	if err != nil {
+		return Solution{}, err
+	}
+
+	return Solution{
+		Status:  result.Status,
+		Success: result.Success,
+		KeyMove: toPublicMove(board, result.KeyMove),
+		Tree:    toVariations(board, result.Tree),
+		Nodes:   result.Nodes,
+	}, nil
+}
+
+// converts a refutation tree to public moves
+func toVariations(board *eng.Board, nodes []*ai.TsumegoNode) []*Variation {
+	if len(nodes) == 0 {
+		return nil
+	}
+	variations := make([]*Variation, 0, len(nodes))
+	for _, n := range nodes {
+		variations = append(variations, &Variation{
+			Move:    toPublicMove(board, n.Move),
+			Replies: toVariations(board, n.Replies),
+		})
+	}
+	return variations
+}
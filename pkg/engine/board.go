@@ -0,0 +1,190 @@
+package engine
+
+import (
+	eng "github.com/awesohame/gogo/internal/engine"
+)
+
+// Board is a read-only view of a board position
+type Board struct {
+	board *eng.Board
+}
+
+// returns the color at the given coords (1-based), Border for points off the board
+func (b *Board) At(x, y int) Color {
+	if !b.Contains(Point{X: x, Y: y}) {
+		return Border
+	}
+	return b.board.At(x, y)
+}
+
+// returns the color at a point, Border for points off the board
+func (b *Board) AtPoint(p Point) Color {
+	return b.At(p.X, p.Y)
+}
+
+// returns whether a point lies on the board
+func (b *Board) Contains(p Point) bool {
+	return p.X >= 1 && p.X <= b.board.Width() && p.Y >= 1 && p.Y <= b.board.Height()
+}
+
+// returns board size, the width on rectangular boards
+func (b *Board) Size() int {
+	return b.board.Size()
+}
+
+// returns no. of columns
+func (b *Board) Width() int {
+	return b.board.Width()
+}
+
+// returns no. of rows
+func (b *Board) Height() int {
+	return b.board.Height()
+}
+
+// returns the zobrist hash of the position
+func (b *Board) Hash() uint64 {
+	return b.board.Hash()
+}
+
+// returns the point where the last capture made a ko, false if there is none
+func (b *Board) KoPoint() (Point, bool) {
+	if b.board.KoPoint() < 0 {
+		return Point{}, false
+	}
+	return b.fromPoint(b.board.KoPoint()), true
+}
+
+// returns a hash shared by every rotation and reflection of the position
+func (b *Board) CanonicalHash() uint64 {
+	return b.board.CanonicalHash()
+}
+
+// returns all points of the board, row by row from the top left
+func (b *Board) Points() []Point {
+	points := make([]Point, 0, b.Width()*b.Height())
+	for y := 1; y <= b.Height(); y++ {
+		for x := 1; x <= b.Width(); x++ {
+			points = append(points, Point{X: x, Y: y})
+		}
+	}
+	return points
+}
+
+// returns the on-board neighbors of a point
+func (b *Board) Neighbors(p Point) []Point {
+	neighbors := make([]Point, 0, 4)
+	for _, n := range [4]Point{{p.X - 1, p.Y}, {p.X + 1, p.Y}, {p.X, p.Y - 1}, {p.X, p.Y + 1}} {
+		if b.Contains(n) {
+			neighbors = append(neighbors, n)
+		}
+	}
+	return neighbors
+}
+
+// returns the points holding stones of the given color
+func (b *Board) Stones(color Color) []Point {
+	stones := make([]Point, 0)
+	for _, p := range b.Points() {
+		if b.board.At(p.X, p.Y) == color {
+			stones = append(stones, p)
+		}
+	}
+	return stones
+}
+
+// Group is a snapshot of a chain of connected stones of one color
+type Group struct {
+	Color     Color
+	Stones    []Point // row by row from the top left
+	Liberties []Point // row by row from the top left
+}
+
+// returns no. of liberties of the group
+func (g Group) LibertyCount() int {
+	return len(g.Liberties)
+}
+
+// returns whether the group has a single liberty left
+func (g Group) InAtari() bool {
+	return len(g.Liberties) == 1
+}
+
+// returns the group holding a stone at p, false for empty points and points off the board
+func (b *Board) GroupAt(p Point) (Group, bool) {
+	if !b.Contains(p) {
+		return Group{}, false
+	}
+	g, ok := b.board.GroupAt(b.toPoint(p))
+	if !ok {
+		return Group{}, false
+	}
+	return b.fromGroup(g), true
+}
+
+// returns no. of liberties of the group at p, 0 for empty points and points off the board
+func (b *Board) Liberties(p Point) int {
+	if !b.Contains(p) {
+		return 0
+	}
+	return b.board.Liberties(b.toPoint(p))
+}
+
+// returns all groups of a color
+func (b *Board) Groups(color Color) []Group {
+	return b.fromGroups(b.board.Groups(color))
+}
+
+// returns the groups of a color with a single liberty
+func (b *Board) GroupsInAtari(color Color) []Group {
+	return b.fromGroups(b.board.GroupsInAtari(color))
+}
+
+// returns the enemy groups touching the group at p, nil for empty points
+func (b *Board) AdjacentEnemyGroups(p Point) []Group {
+	if !b.Contains(p) {
+		return nil
+	}
+	return b.fromGroups(b.board.AdjacentEnemyGroups(b.toPoint(p)))
+}
+
+// returns a string representation of the board for display
+func (b *Board) String() string {
+	return b.board.String()
+}
+
+// converts a public point to an engine point
+func (b *Board) toPoint(p Point) eng.Point {
+	return b.board.ToPoint(p.X, p.Y)
+}
+
+// converts an engine point to a public point
+func (b *Board) fromPoint(p eng.Point) Point {
+	x, y := b.board.ToXY(p)
+	return Point{X: x, Y: y}
+}
+
+// converts an engine group to a public group
+func (b *Board) fromGroup(g eng.Group) Group {
+	group := Group{
+		Color:     g.Color,
+		Stones:    make([]Point, 0, g.Stones.Count()),
+		Liberties: make([]Point, 0, g.Liberties.Count()),
+	}
+	g.Stones.ForEach(func(p eng.Point) {
+		group.Stones = append(group.Stones, b.fromPoint(p))
+	})
+	g.Liberties.ForEach(func(p eng.Point) {
+		group.Liberties = append(group.Liberties, b.fromPoint(p))
+	})
+	return group
+}
+
+// converts engine groups to public groups
+func (b *Board) fromGroups(groups []eng.Group) []Group {
+	public := make([]Group, len(groups))
+	for i, g := range groups {
+		public[i] = b.fromGroup(g)
+	}
+	return public
+}
@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"strings"
+
+	eng "github.com/awesohame/gogo/internal/engine"
+)
+
+// formats a point as GTP vertex like "D4" on a board of the given height
+// columns are letters from the left skipping I, rows count up from the bottom
+func FormatGTP(p Point, height int) string {
+	return eng.FormatGTP(p.X, p.Y, height)
+}
+
+// formats a point as SGF point like "dd", both letters count from the top left
+func FormatSGF(p Point) string {
+	return eng.FormatSGF(p.X, p.Y)
+}
+
+// parses a point in GTP ("D4"), SGF ("dd") or numeric ("4 6", x from the left and
+// y from the top) form for a board of the given dimensions
+func ParsePoint(s string, width, height int) (Point, error) {
+	x, y, err := eng.ParseCoord(s, width, height)
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{X: x, Y: y}, nil
+}
+
+// formats a point of this board as GTP vertex like "D4"
+func (b *Board) FormatGTP(p Point) string {
+	return FormatGTP(p, b.Height())
+}
+
+// parses a point of this board in GTP, SGF or numeric form
+func (b *Board) ParsePoint(s string) (Point, error) {
+	return ParsePoint(s, b.Width(), b.Height())
+}
+
+// parses a move for the player to move, "pass" or a point in GTP, SGF or numeric form
+func (g *Game) ParseMove(s string) (Move, error) {
+	turn := g.CurrentTurn()
+	if strings.EqualFold(strings.TrimSpace(s), "pass") {
+		return PassMove(turn), nil
+	}
+	p, err := ParsePoint(s, g.Width(), g.Height())
+	if err != nil {
+		return Move{}, err
+	}
+	return Move{Point: p, Color: turn}, nil
+}
+
+// formats a move as "Black D4" or "Black pass" for this game's board
+func (g *Game) FormatMove(m Move) string {
+	if m.Pass {
+		return m.String()
+	}
+	return ColorName(m.Color) + " " + FormatGTP(m.Point, g.Height())
+}
@@ -1,23 +1,204 @@
 package engine
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/awesohame/gogo/internal/bridge"
 	eng "github.com/awesohame/gogo/internal/engine"
 	"github.com/awesohame/gogo/internal/game"
 )
 
+func init() {
+	// let sibling public packages (pkg/bot) work on the game's session
+	bridge.GameSession = func(g any) *game.Session {
+		return g.(*Game).session
+	}
+}
+
 // public API for managing a Go game
 type Game struct {
 	session *game.Session
 }
 
-// creates new Go game custom board size
-func NewGame(size int) *Game {
-	return &Game{session: game.NewSession(size)}
+// Option configures a new game
+type Option func(*game.Config)
+
+// sets a rectangular board, overriding the size passed to NewGame
+func WithRectSize(width, height int) Option {
+	return func(c *game.Config) {
+		c.Width = width
+		c.Height = height
+	}
+}
+
+// sets the points added to white's score
+func WithKomi(komi float64) Option {
+	return func(c *game.Config) {
+		c.Komi = komi
+	}
 }
 
-// applies a move to the current game
-func (g *Game) MakeMove(move eng.Move) error {
-	return g.session.MakeMove(move)
+// sets the scoring rules (Chinese by default)
+func WithRules(rules Rules) Option {
+	return func(c *game.Config) {
+		c.Rules = rules
+	}
+}
+
+// places n black stones on the standard handicap points, white then moves first
+func WithHandicap(n int) Option {
+	return func(c *game.Config) {
+		c.Handicap = n
+	}
+}
+
+// creates new Go game custom board size, configured by options
+// panics if the options are invalid (board size out of range, unsupported handicap)
+func NewGame(size int, opts ...Option) *Game {
+	g, err := NewGameWithOptions(size, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// creates new Go game like NewGame, returning invalid options as an error
+func NewGameWithOptions(size int, opts ...Option) (*Game, error) {
+	cfg := game.Config{Width: size, Height: size}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	session, err := game.NewSessionWithConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Game{session: session}, nil
+}
+
+// creates new Go game on a width x height board
+func NewRectGame(width, height int, opts ...Option) *Game {
+	return NewGame(width, append([]Option{WithRectSize(width, height)}, opts...)...)
+}
+
+// creates a game starting from an ASCII diagram with the given side to move
+// diagrams use X for black, O for white, . for empty and * for a ko point,
+// the board printed by Board.String parses back; komi and rules options apply,
+// size and handicap options are rejected
+func NewGameFromDiagram(diagram string, toMove Color, opts ...Option) (*Game, error) {
+	var cfg game.Config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.Width != 0 || cfg.Height != 0 || cfg.Handicap != 0 {
+		return nil, errors.New("size and handicap come from the diagram")
+	}
+
+	board, err := eng.ParseDiagram(diagram)
+	if err != nil {
+		return nil, err
+	}
+	session, err := game.NewSessionFromBoard(board, toMove, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Game{session: session}, nil
+}
+
+// applies a move to the current game, a pass move passes
+func (g *Game) MakeMove(move Move) error {
+	_, err := g.MakeMoveWithResult(move)
+	return err
+}
+
+// applies a move like MakeMove and returns the stones it captured, any ko and the
+// liberties of the placed group, passes return an empty result
+func (g *Game) MakeMoveWithResult(move Move) (MoveResult, error) {
+	if move.Pass {
+		if !g.session.IsGameOver() && move.Color != g.session.CurrentTurn() {
+			return MoveResult{}, ErrWrongTurn
+		}
+		return MoveResult{}, g.session.Pass()
+	}
+
+	board := g.session.CurrentBoard()
+	view := Board{board: board}
+	if !view.Contains(move.Point) {
+		return MoveResult{}, ErrOffBoard
+	}
+	result, err := g.session.MakeMoveWithResult(eng.Move{Point: view.toPoint(move.Point), Color: move.Color})
+	if err != nil {
+		return MoveResult{}, err
+	}
+
+	public := MoveResult{Liberties: result.Liberties}
+	for _, p := range result.Captured {
+		public.Captured = append(public.Captured, view.fromPoint(p))
+	}
+	if result.KoPoint >= 0 {
+		public.KoPoint = view.fromPoint(result.KoPoint)
+	}
+	return public, nil
+}
+
+// checks a move against the current board without playing it, ignoring turn order
+// passes are always legal
+func (g *Game) CheckLegality(move Move) Legality {
+	if move.Pass {
+		return Legal
+	}
+	board := g.CurrentBoard()
+	if !board.Contains(move.Point) {
+		return OffBoard
+	}
+	return board.board.CheckLegality(board.toPoint(move.Point), move.Color)
+}
+
+// reports whether a move could be played on the current board, ignoring turn order
+func (g *Game) IsLegal(move Move) bool {
+	return g.CheckLegality(move) == Legal
+}
+
+// returns every point where color may place a stone, row by row from the top left
+func (g *Game) LegalMoves(color Color) []Point {
+	board := g.session.CurrentBoard()
+	points := board.LegalMoves(color)
+	view := Board{board: board}
+	moves := make([]Point, len(points))
+	for i, p := range points {
+		moves[i] = view.fromPoint(p)
+	}
+	return moves
+}
+
+// edits the position regardless of the rules and sets the side to move, for setting up
+// problems and studying positions; the edit is undone and redone like a move
+func (g *Game) Edit(setup Setup, toMove Color) error {
+	view := Board{board: g.session.CurrentBoard()}
+	convert := func(points []Point) ([]eng.Point, error) {
+		converted := make([]eng.Point, len(points))
+		for i, p := range points {
+			if !view.Contains(p) {
+				return nil, fmt.Errorf("%w: %v", ErrOffBoard, p)
+			}
+			converted[i] = view.toPoint(p)
+		}
+		return converted, nil
+	}
+
+	var engineSetup eng.Setup
+	var err error
+	if engineSetup.Black, err = convert(setup.Black); err != nil {
+		return err
+	}
+	if engineSetup.White, err = convert(setup.White); err != nil {
+		return err
+	}
+	if engineSetup.Empty, err = convert(setup.Empty); err != nil {
+		return err
+	}
+	return g.session.Edit(engineSetup, toMove)
 }
 
 // undoes the last move
@@ -40,13 +221,13 @@ func (g *Game) Resign() {
 	g.session.Resign()
 }
 
-// returns the current board state
-func (g *Game) CurrentBoard() *eng.Board {
-	return g.session.CurrentBoard()
+// returns a read-only view of the current board state
+func (g *Game) CurrentBoard() *Board {
+	return &Board{board: g.session.CurrentBoard()}
 }
 
 // returns whose turn it is
-func (g *Game) CurrentTurn() eng.Color {
+func (g *Game) CurrentTurn() Color {
 	return g.session.CurrentTurn()
 }
 
@@ -56,15 +237,35 @@ func (g *Game) IsGameOver() bool {
 }
 
 // returns the current score
-func (g *Game) GetScore() eng.Score {
+func (g *Game) GetScore() Score {
 	return g.session.GetScore()
 }
 
 // returns the score with komi
-func (g *Game) GetScoreWithKomi(komi float64) (float64, float64, eng.Color) {
+func (g *Game) GetScoreWithKomi(komi float64) (float64, float64, Color) {
 	return g.session.GetScoreWithKomi(komi)
 }
 
+// returns the score with the komi the game was created with
+func (g *Game) GetFinalScore() (float64, float64, Color) {
+	return g.session.GetFinalScore()
+}
+
+// returns the komi added to white's score
+func (g *Game) Komi() float64 {
+	return g.session.Komi()
+}
+
+// returns the scoring rules
+func (g *Game) Rules() Rules {
+	return g.session.Rules()
+}
+
+// returns the no. of handicap stones placed at the start
+func (g *Game) Handicap() int {
+	return g.session.Handicap()
+}
+
 // returns the number of moves made
 func (g *Game) MoveCount() int {
 	return g.session.MoveCount()
@@ -80,16 +281,22 @@ func (g *Game) CanRedo() bool {
 	return g.session.CanRedo()
 }
 
-// returns the board size
+// returns the board size, the width on rectangular boards
 func (g *Game) Size() int {
 	return g.session.Size()
 }
 
+// returns the board width
+func (g *Game) Width() int {
+	return g.session.Width()
+}
+
+// returns the board height
+func (g *Game) Height() int {
+	return g.session.Height()
+}
+
 // creates a Move at the given coords (1-based)
-func (g *Game) NewMove(x, y int, color eng.Color) eng.Move {
-	board := g.session.CurrentBoard()
-	return eng.Move{
-		Point: board.ToPoint(x, y),
-		Color: color,
-	}
+func (g *Game) NewMove(x, y int, color Color) Move {
+	return Move{Point: Point{X: x, Y: y}, Color: color}
 }
@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"fmt"
+
+	eng "github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/internal/game"
+)
+
+// Color is the state of a point
+type Color = eng.Color
+
+const (
+	Empty  = eng.Empty
+	Black  = eng.Black
+	White  = eng.White
+	Border = eng.Border // returned for points off the board
+)
+
+// Score is the area count of both players
+type Score = eng.Score
+
+// Rules selects how a finished game is scored
+type Rules = game.Rules
+
+const (
+	ChineseRules     = game.ChineseRules     // area scoring, dead stones removed first
+	TrompTaylorRules = game.TrompTaylorRules // area scoring, every stone on the board counts
+)
+
+// Legality is the result of a legality check, Legal or the reason a move is illegal
+type Legality = eng.Legality
+
+const (
+	Legal       = eng.Legal
+	Occupied    = eng.Occupied
+	OffBoard    = eng.OffBoard
+	Suicide     = eng.Suicide
+	KoViolation = eng.KoViolation // recreates an earlier position (positional superko)
+)
+
+// errors returned for illegal moves and session actions, use errors.Is to tell them apart
+var (
+	ErrOccupied      = eng.ErrOccupied
+	ErrOffBoard      = eng.ErrOffBoard
+	ErrSuicide       = eng.ErrSuicide
+	ErrKo            = eng.ErrKo
+	ErrGameOver      = game.ErrGameOver
+	ErrWrongTurn     = game.ErrWrongTurn
+	ErrNothingToUndo = game.ErrNothingToUndo
+	ErrNothingToRedo = game.ErrNothingToRedo
+)
+
+// KoError reports a move that recreates an earlier position and the move it repeats,
+// get it with errors.As, it also matches ErrKo
+type KoError = eng.KoError
+
+// supported board dimensions, boards may be rectangular
+const (
+	MinBoardSize = eng.MinBoardSize
+	MaxBoardSize = eng.MaxBoardSize
+)
+
+// Point is a 1-based board coordinate, X counts columns from the left and Y rows from the top
+type Point struct {
+	X int
+	Y int
+}
+
+// returns the point as "(x, y)"
+func (p Point) String() string {
+	return fmt.Sprintf("(%d, %d)", p.X, p.Y)
+}
+
+// Move is a player's action, placing a stone or passing
+type Move struct {
+	Point Point
+	Color Color
+	Pass  bool
+}
+
+// creates a pass for the given color
+func PassMove(color Color) Move {
+	return Move{Color: color, Pass: true}
+}
+
+// returns the move as "Black (x, y)" or "Black pass"
+func (m Move) String() string {
+	if m.Pass {
+		return fmt.Sprintf("%s pass", ColorName(m.Color))
+	}
+	return fmt.Sprintf("%s %s", ColorName(m.Color), m.Point)
+}
+
+// MoveResult describes what a move did to the board
+type MoveResult struct {
+	Captured  []Point // stones removed from the board
+	KoPoint   Point   // point the opponent may not retake at immediately, the zero Point if none
+	Liberties int     // liberties of the group containing the placed stone
+}
+
+// returns whether the move left a ko
+func (r MoveResult) HasKo() bool {
+	return r.KoPoint != Point{}
+}
+
+// Setup lists stones to add or remove regardless of the rules, like SGF AB/AW/AE
+type Setup struct {
+	Black []Point // points to hold black stones
+	White []Point // points to hold white stones
+	Empty []Point // points to clear
+}
+
+// returns the other player's color
+func Opponent(c Color) Color {
+	if c == Black {
+		return White
+	}
+	return Black
+}
+
+// returns "Black", "White" or "Empty"
+func ColorName(c Color) string {
+	switch c {
+	case Black:
+		return "Black"
+	case White:
+		return "White"
+	case Border:
+		return "Border"
+	default:
+		return "Empty"
+	}
+}
+
+// parses a board size like "19" or "19x13" (width x height)
+func ParseBoardSize(s string) (width int, height int, err error) {
+	return eng.ParseBoardSize(s)
+}
@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/awesohame/gogo/internal/ai"
+	eng "github.com/awesohame/gogo/internal/engine"
+)
+
+// TestAlphaBetaCapture tests that the bot takes a large group in atari
+func TestAlphaBetaCapture(t *testing.T) {
+	board, err := eng.ParseDiagram(`
+		. X X X .
+		X O O O .
+		. X X X .
+		. . . . .
+		. . . . .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the three white stones have one liberty left
+	for _, evaluator := range []ai.Evaluator{&ai.SimpleEvaluator{}, ai.NewHybridEvaluator()} {
+		bot := ai.NewAlphaBetaBot(evaluator)
+		bot.MaxDepth = 2
+		move := bot.SelectMove(board, eng.Black)
+		if move.Point != board.ToPoint(5, 2) {
+			t.Errorf("%T: expected black to capture at %s, got %s", evaluator, board.FormatGTP(board.ToPoint(5, 2)), board.FormatGTP(move.Point))
+		}
+	}
+}
+
+// TestAlphaBetaEscape tests that the bot saves its own stones in atari
+func TestAlphaBetaEscape(t *testing.T) {
+	board, err := eng.ParseDiagram(`
+		. . . . .
+		. . X . .
+		. X O . .
+		. . X . .
+		. . . . .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot := ai.NewAlphaBetaBot(&ai.SimpleEvaluator{})
+	bot.MaxDepth = 2
+	move := bot.SelectMove(board, eng.White)
+	if move.Point != board.ToPoint(4, 3) {
+		t.Errorf("expected white to extend at %s, got %s", board.FormatGTP(board.ToPoint(4, 3)), board.FormatGTP(move.Point))
+	}
+}
+
+// TestAlphaBetaDeterministic tests that a node budget gives the same move every time
+func TestAlphaBetaDeterministic(t *testing.T) {
+	board := eng.NewBoard(7)
+	bot := ai.NewAlphaBetaBot(ai.NewHybridEvaluator())
+	bot.MaxDepth = 0
+	bot.MaxNodes = 3000
+
+	first := bot.Search(board, eng.Black)
+	if first.Move.Point < 0 || !board.IsLegal(first.Move.Point, eng.Black) {
+		t.Fatalf("expected a legal move, got %s", board.FormatGTP(first.Move.Point))
+	}
+	if first.Depth < 1 {
+		t.Errorf("expected at least one finished iteration, got depth %d", first.Depth)
+	}
+	if first.Nodes > bot.MaxNodes {
+		t.Errorf("expected at most %d nodes, got %d", bot.MaxNodes, first.Nodes)
+	}
+	for i := 0; i < 3; i++ {
+		if again := bot.Search(board, eng.Black); again.Move != first.Move || again.Nodes != first.Nodes {
+			t.Fatalf("expected %s in %d nodes again, got %s in %d", board.FormatGTP(first.Move.Point), first.Nodes,
+				board.FormatGTP(again.Move.Point), again.Nodes)
+		}
+	}
+}
+
+// TestAlphaBetaTimeLimit tests that an unbounded depth stops at the time limit
+func TestAlphaBetaTimeLimit(t *testing.T) {
+	bot := ai.NewAlphaBetaBot(&ai.SimpleEvaluator{})
+	bot.MaxDepth = 0
+	bot.MaxNodes = 0
+	bot.TimeLimit = 0.2
+
+	board := eng.NewBoard(9)
+	result := bot.Search(board, eng.Black)
+	if result.Move.Point >= 0 && !board.IsLegal(result.Move.Point, eng.Black) {
+		t.Errorf("expected a legal move, got %s", board.FormatGTP(result.Move.Point))
+	}
+}
+
+// TestAlphaBetaMatchesMinimax tests that pruning keeps the score of a plain minimax search
+func TestAlphaBetaMatchesMinimax(t *testing.T) {
+	evaluator := ai.NewHybridEvaluator()
+	rng := rand.New(rand.NewSource(45))
+	for i := 0; i < 20; i++ {
+		board := eng.NewBoard(5)
+		// 4 to 13 random moves, so both players get to search
+		color := eng.Black
+		for n := 0; n < 4+i%10; n++ {
+			moves := board.LegalMoves(color)
+			next, err := board.ApplyMove(eng.Move{Point: moves[rng.Intn(len(moves))], Color: color})
+			if err != nil {
+				continue
+			}
+			board = next
+			color = opponent(color)
+		}
+
+		bot := ai.NewAlphaBetaBot(evaluator)
+		bot.MaxNodes = 0
+		result := bot.Search(board, color)
+		if want := minimax(evaluator, board, color, false, bot.MaxDepth); result.Score != want {
+			t.Errorf("position %d: expected score %v, got %v\n%s", i, want, result.Score, board)
+		}
+	}
+}
+
+// returns the plain minimax score for the player to move over the moves the alpha-beta bot searches
+func minimax(evaluator ai.Evaluator, board *eng.Board, toMove eng.Color, passed bool, depth int) float64 {
+	if depth <= 0 {
+		return evaluator.Evaluate(board, toMove)
+	}
+	best := math.Inf(-1)
+	for _, p := range board.LegalMoves(toMove) {
+		move := eng.Move{Point: p, Color: toMove}
+		if ai.IsEyeFillingMove(board, move) {
+			continue
+		}
+		next, err := board.ApplyMove(move)
+		if err != nil {
+			continue
+		}
+		best = math.Max(best, -minimax(evaluator, next, opponent(toMove), false, depth-1))
+	}
+	// a second pass in a row ends the game
+	if passed {
+		return math.Max(best, evaluator.Evaluate(board, toMove))
+	}
+	return math.Max(best, -minimax(evaluator, board, opponent(toMove), true, depth-1))
+}
+
+// returns the other player
+func opponent(color eng.Color) eng.Color {
+	if color == eng.Black {
+		return eng.White
+	}
+	return eng.Black
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/awesohame/gogo/pkg/engine"
+)
+
+// TestGameOptions tests komi, rules and handicap options
+func TestGameOptions(t *testing.T) {
+	game := engine.NewGame(19, engine.WithKomi(0.5), engine.WithHandicap(4), engine.WithRules(engine.TrompTaylorRules))
+
+	if game.Komi() != 0.5 || game.Rules() != engine.TrompTaylorRules || game.Handicap() != 4 {
+		t.Errorf("options not applied: komi %v rules %v handicap %d", game.Komi(), game.Rules(), game.Handicap())
+	}
+	if game.CurrentTurn() != engine.White {
+		t.Errorf("white should move first in a handicap game, got %v", game.CurrentTurn())
+	}
+	if game.MoveCount() != 0 || game.CanUndo() {
+		t.Error("handicap stones should not count as moves")
+	}
+
+	board := game.CurrentBoard()
+	for _, p := range []engine.Point{{X: 4, Y: 4}, {X: 16, Y: 4}, {X: 4, Y: 16}, {X: 16, Y: 16}} {
+		if board.AtPoint(p) != engine.Black {
+			t.Errorf("expected handicap stone at %v", p)
+		}
+	}
+	if got := len(board.Stones(engine.Black)); got != 4 {
+		t.Errorf("expected 4 black stones, got %d", got)
+	}
+
+	// 9 stones on 9x9 use the 3rd line and the center
+	game = engine.NewGame(9, engine.WithHandicap(9))
+	if got := game.CurrentBoard().At(5, 5); got != engine.Black {
+		t.Errorf("expected center handicap stone, got %v", got)
+	}
+}
+
+// TestGameOptionErrors tests rejection of invalid options
+func TestGameOptionErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		size int
+		opts []engine.Option
+	}{
+		{"too large", 30, nil},
+		{"handicap on even board", 8, []engine.Option{engine.WithHandicap(5)}},
+		{"handicap on tiny board", 5, []engine.Option{engine.WithHandicap(2)}},
+		{"bad rectangle", 9, []engine.Option{engine.WithRectSize(9, 1)}},
+	}
+
+	for _, tc := range cases {
+		if _, err := engine.NewGameWithOptions(tc.size, tc.opts...); err == nil {
+			t.Errorf("%s: expected error", tc.name)
+		}
+	}
+}
+
+// TestRulesScoring tests that Tromp-Taylor counts stones the Chinese count removes
+func TestRulesScoring(t *testing.T) {
+	play := func(game *engine.Game) {
+		moves := []engine.Move{
+			{Point: engine.Point{X: 2, Y: 2}, Color: engine.Black},
+			{Point: engine.Point{X: 1, Y: 1}, Color: engine.White},
+			{Point: engine.Point{X: 2, Y: 1}, Color: engine.Black},
+			{Point: engine.Point{X: 5, Y: 5}, Color: engine.White},
+			{Point: engine.Point{X: 1, Y: 3}, Color: engine.Black},
+		}
+		for _, m := range moves {
+			if err := game.MakeMove(m); err != nil {
+				t.Fatalf("move %v failed: %v", m, err)
+			}
+		}
+	}
+
+	chinese := engine.NewGame(5)
+	trompTaylor := engine.NewGame(5, engine.WithRules(engine.TrompTaylorRules))
+	play(chinese)
+	play(trompTaylor)
+
+	// white (1,1) is in atari inside black's area
+	if got := chinese.GetScore().WhiteStones; got != 1 {
+		t.Errorf("chinese count should remove the dead stone, got %d white stones", got)
+	}
+	if got := trompTaylor.GetScore().WhiteStones; got != 2 {
+		t.Errorf("tromp-taylor count should keep the stone, got %d white stones", got)
+	}
+}
+
+// TestBoardView tests the read-only board and coordinate helpers
+func TestBoardView(t *testing.T) {
+	game := engine.NewRectGame(7, 5)
+	if err := game.MakeMove(game.NewMove(7, 5, engine.Black)); err != nil {
+		t.Fatalf("corner move failed: %v", err)
+	}
+
+	board := game.CurrentBoard()
+	if board.Width() != 7 || board.Height() != 5 {
+		t.Errorf("expected 7x5, got %dx%d", board.Width(), board.Height())
+	}
+	if got := len(board.Points()); got != 35 {
+		t.Errorf("expected 35 points, got %d", got)
+	}
+	if got := board.Neighbors(engine.Point{X: 7, Y: 5}); len(got) != 2 {
+		t.Errorf("corner should have 2 neighbors, got %v", got)
+	}
+	if board.At(0, 3) != engine.Border || board.At(100, 100) != engine.Border {
+		t.Error("points off the board should read as border")
+	}
+
+	if err := game.MakeMove(game.NewMove(8, 1, engine.White)); err == nil {
+		t.Error("move off the board should fail")
+	}
+
+	// pass moves go through MakeMove too
+	if err := game.MakeMove(engine.PassMove(engine.White)); err != nil {
+		t.Fatalf("pass failed: %v", err)
+	}
+	if game.CurrentTurn() != engine.Black {
+		t.Errorf("expected black after white pass, got %v", game.CurrentTurn())
+	}
+	if got := engine.PassMove(engine.Black).String(); got != "Black pass" {
+		t.Errorf("unexpected pass string %q", got)
+	}
+}
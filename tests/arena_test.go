@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/awesohame/gogo/internal/arena"
+	eng "github.com/awesohame/gogo/internal/engine"
+)
+
+// TestArenaRun tests that a match alternates colours and counts every game
+func TestArenaRun(t *testing.T) {
+	a, err := arena.ParsePlayer("greedy", 0, 7.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := arena.ParsePlayer("random:seed=3,name=rand", 0, 7.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var records []arena.GameRecord
+	stats, err := arena.Run(arena.Config{Width: 5, Games: 6, Concurrency: 3, Komi: 0.5}, a, b,
+		func(r arena.GameRecord, _ arena.Stats) bool {
+			records = append(records, r)
+			return true
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Games != 6 || len(records) != 6 || stats.Wins+stats.Losses+stats.Draws != 6 {
+		t.Fatalf("expected 6 counted games, got %+v and %d records", stats, len(records))
+	}
+	for _, r := range records {
+		wantBlack := "greedy"
+		if r.Round%2 == 1 {
+			wantBlack = "rand"
+		}
+		if r.Black != wantBlack {
+			t.Errorf("round %d: expected %s to play black, got %s", r.Round, wantBlack, r.Black)
+		}
+		if r.Winner == eng.Empty {
+			t.Errorf("round %d: expected no draw with half a point of komi", r.Round)
+		}
+	}
+}
+
+// TestArenaStop tests that a report returning false stops new games
+func TestArenaStop(t *testing.T) {
+	a, _ := arena.ParsePlayer("random", 0, 7.5)
+	b, _ := arena.ParsePlayer("random:seed=9", 0, 7.5)
+	stats, err := arena.Run(arena.Config{Width: 5, Games: 50}, a, b,
+		func(_ arena.GameRecord, s arena.Stats) bool { return s.Games < 3 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Games != 3 {
+		t.Errorf("expected the match to stop after 3 games, got %d", stats.Games)
+	}
+}
+
+// TestArenaBadConfig tests that an impossible board size is an error
+func TestArenaBadConfig(t *testing.T) {
+	a, _ := arena.ParsePlayer("random", 0, 7.5)
+	if _, err := arena.Run(arena.Config{Width: 1, Games: 1}, a, a, nil); err == nil {
+		t.Error("expected an error for a 1x1 board")
+	}
+}
+
+// TestParsePlayer tests bot descriptions
+func TestParsePlayer(t *testing.T) {
+	valid := []string{"mcts", "mcts:sims=50,c=1.0,reuse=false", "alphabeta:depth=2,eval=simple",
+		"heuristic:eval=influence", "greedy:name=g", "random:seed=5"}
+	for _, spec := range valid {
+		if _, err := arena.ParsePlayer(spec, 0, 7.5); err != nil {
+			t.Errorf("%q: unexpected error %v", spec, err)
+		}
+	}
+
+	invalid := []string{"gnugo", "mcts:sims=many", "mcts:depth=3", "alphabeta:eval=neural", "greedy:fast", "mcts:reuse=maybe"}
+	for _, spec := range invalid {
+		if _, err := arena.ParsePlayer(spec, 0, 7.5); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+
+	if p, _ := arena.ParsePlayer("greedy:name=baseline", 0, 7.5); p.Name != "baseline" {
+		t.Errorf("expected the name option to label the player, got %q", p.Name)
+	}
+}
+
+// TestArenaElo tests the conversions between score and Elo
+func TestArenaElo(t *testing.T) {
+	if elo := arena.ScoreToElo(0.75); math.Abs(elo-190.85) > 0.01 {
+		t.Errorf("expected 75%% to be about +190.85 Elo, got %.2f", elo)
+	}
+	for _, elo := range []float64{-300, 0, 35, 400} {
+		if back := arena.ScoreToElo(arena.EloToScore(elo)); math.Abs(back-elo) > 1e-9 {
+			t.Errorf("expected %g Elo back, got %g", elo, back)
+		}
+	}
+
+	stats := arena.Stats{Games: 100, Wins: 60, Losses: 30, Draws: 10}
+	if stats.Score() != 0.65 {
+		t.Errorf("expected a score of 0.65, got %v", stats.Score())
+	}
+	lo, hi := stats.ScoreInterval()
+	if lo >= 0.65 || hi <= 0.65 || hi-lo > 0.2 {
+		t.Errorf("expected a narrow interval around 0.65, got [%v, %v]", lo, hi)
+	}
+	elo, eloLo, eloHi := stats.Elo()
+	if !(eloLo < elo && elo < eloHi) || eloLo <= 0 {
+		t.Errorf("expected a positive interval around %v, got [%v, %v]", elo, eloLo, eloHi)
+	}
+}
+
+// TestSPRT tests that clear results end the test and close ones do not
+func TestSPRT(t *testing.T) {
+	sprt := arena.NewSPRT(0, 20)
+	cases := []struct {
+		stats arena.Stats
+		want  arena.Verdict
+	}{
+		{arena.Stats{Games: 400, Wins: 260, Losses: 140}, arena.AcceptH1},
+		{arena.Stats{Games: 400, Wins: 180, Losses: 220}, arena.AcceptH0},
+		{arena.Stats{Games: 20, Wins: 11, Losses: 9}, arena.Continue},
+		{arena.Stats{}, arena.Continue},
+	}
+	for _, tc := range cases {
+		if got := sprt.Verdict(tc.stats); got != tc.want {
+			t.Errorf("%+v: expected %v, got %v (LLR %.2f)", tc.stats, tc.want, got, sprt.LLR(tc.stats))
+		}
+	}
+}
+
+// TestWriteSGF tests the SGF record of a game
+func TestWriteSGF(t *testing.T) {
+	board := eng.NewRectBoard(7, 5)
+	record := arena.GameRecord{
+		Black: "a]b", White: "c", Width: 7, Height: 5, Komi: 6.5,
+		Moves: []eng.Move{
+			{Point: board.ToPoint(3, 2), Color: eng.Black},
+			{Point: -1, Color: eng.White},
+		},
+		Winner: eng.White, Margin: 2.5,
+	}
+	var sb strings.Builder
+	if err := arena.WriteSGF(&sb, record); err != nil {
+		t.Fatal(err)
+	}
+	sgf := sb.String()
+	for _, want := range []string{"SZ[7:5]", "KM[6.5]", `PB[a\]b]`, "RE[W+2.5]", ";B[cb];W[])"} {
+		if !strings.Contains(sgf, want) {
+			t.Errorf("expected %s in %s", want, sgf)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/awesohame/gogo/internal/ai"
+	eng "github.com/awesohame/gogo/internal/engine"
+)
+
+// black stones with two eyes at A5 and C5, every other point is white's to fill
+const twoEyedCorner = `
+	. X . X .
+	X X X X .
+	. . . . .
+	. . . . .
+	. . . . .`
+
+// TestRandomBot tests that the random bot plays legal moves, never in its own eyes, and follows its seed
+func TestRandomBot(t *testing.T) {
+	board, err := eng.ParseDiagram(twoEyedCorner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eyes := []eng.Point{board.ToPoint(1, 1), board.ToPoint(3, 1)}
+
+	first := ai.NewRandomBot(7)
+	second := ai.NewRandomBot(7)
+	for i := 0; i < 50; i++ {
+		move := first.SelectMove(board, eng.Black)
+		if move.Point < 0 || !board.IsLegal(move.Point, eng.Black) {
+			t.Fatalf("expected a legal move, got %s", board.FormatGTP(move.Point))
+		}
+		for _, eye := range eyes {
+			if move.Point == eye {
+				t.Fatalf("expected the eye at %s to be left alone", board.FormatGTP(eye))
+			}
+		}
+		if again := second.SelectMove(board, eng.Black); again != move {
+			t.Fatalf("expected the same seed to give %s, got %s", board.FormatGTP(move.Point), board.FormatGTP(again.Point))
+		}
+	}
+}
+
+// TestGreedyBot tests that the greedy bot prefers the bigger capture, then more liberties
+func TestGreedyBot(t *testing.T) {
+	board, err := eng.ParseDiagram(`
+		. X O . .
+		X O O X .
+		. X X . .
+		. . . . .
+		O X . . .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// D5 takes three stones, B4 only takes the one in the corner
+	move := ai.NewGreedyBot().SelectMove(board, eng.Black)
+	if want := board.ToPoint(4, 1); move.Point != want {
+		t.Errorf("expected the three stone capture at %s, got %s", board.FormatGTP(want), board.FormatGTP(move.Point))
+	}
+
+	// nothing to capture on an empty board, the first point with four liberties
+	empty := eng.NewBoard(5)
+	move = ai.NewGreedyBot().SelectMove(empty, eng.Black)
+	if want := empty.ToPoint(2, 2); move.Point != want {
+		t.Errorf("expected %s on the empty board, got %s", empty.FormatGTP(want), empty.FormatGTP(move.Point))
+	}
+}
+
+// TestHeuristicBot tests that the heuristic bot takes a free capture
+func TestHeuristicBot(t *testing.T) {
+	board, err := eng.ParseDiagram(`
+		. X X X .
+		X O O O .
+		. X X X .
+		. . . . .
+		. . . . .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	move := ai.NewHeuristicBot().SelectMove(board, eng.Black)
+	if want := board.ToPoint(5, 2); move.Point != want {
+		t.Errorf("expected the capture at %s, got %s", board.FormatGTP(want), board.FormatGTP(move.Point))
+	}
+}
+
+// TestBaselineBotsPass tests that every baseline bot passes when only its own eyes are left
+func TestBaselineBotsPass(t *testing.T) {
+	board, err := eng.ParseDiagram(`
+		. X . X
+		X X X X
+		X . X X
+		X X X .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bot := range []ai.Bot{ai.NewRandomBot(1), ai.NewGreedyBot(), ai.NewHeuristicBot()} {
+		if move := bot.SelectMove(board, eng.Black); move.Point != -1 {
+			t.Errorf("%T: expected a pass, got %s", bot, board.FormatGTP(move.Point))
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	eng "github.com/awesohame/gogo/internal/engine"
+)
+
+// TestBitset tests basic point set operations
+func TestBitset(t *testing.T) {
+	var s eng.Bitset
+	points := []eng.Point{0, 63, 64, 200, 440}
+	for _, p := range points {
+		s.Set(p)
+	}
+
+	if s.Count() != len(points) {
+		t.Errorf("expected %d points, got %d", len(points), s.Count())
+	}
+	if s.First() != 0 {
+		t.Errorf("expected first point 0, got %d", s.First())
+	}
+	for i, p := range s.Points() {
+		if p != points[i] {
+			t.Errorf("point %d: expected %d, got %d", i, points[i], p)
+		}
+	}
+
+	s.Clear(0)
+	if s.Has(0) || !s.Has(64) {
+		t.Error("clear removed the wrong point")
+	}
+
+	var o eng.Bitset
+	o.Set(64)
+	if got := s.AndNot(o); got.Has(64) || got.Count() != 3 {
+		t.Errorf("AndNot gave %v", got.Points())
+	}
+	if got := s.And(o); got.Count() != 1 || !got.Has(64) {
+		t.Errorf("And gave %v", got.Points())
+	}
+}
+
+// TestBoardGroupsAfterMerge tests that liberties of merged groups exclude their own stones
+func TestBoardGroupsAfterMerge(t *testing.T) {
+	board := eng.NewBoard(5)
+	for _, m := range []struct{ x, y int }{{2, 2}, {3, 2}, {2, 3}} {
+		board, _ = board.ApplyMove(eng.Move{Point: board.ToPoint(m.x, m.y), Color: eng.Black})
+	}
+
+	// one white stone inside the corner is captured once its last liberty is filled
+	board, _ = board.ApplyMove(eng.Move{Point: board.ToPoint(1, 1), Color: eng.White})
+	board, _ = board.ApplyMove(eng.Move{Point: board.ToPoint(1, 2), Color: eng.Black})
+	board, _ = board.ApplyMove(eng.Move{Point: board.ToPoint(2, 1), Color: eng.Black})
+	if board.At(1, 1) != eng.Empty {
+		t.Errorf("corner stone should be captured\n%s", board.String())
+	}
+
+	score := board.CalculateChineseScore()
+	if score.BlackStones != 5 || score.Black != 25 {
+		t.Errorf("expected 5 black stones owning the board, got %+v", score)
+	}
+}
+
+// BenchmarkBoardApplyMove replays a random game move by move
+func BenchmarkBoardApplyMove(b *testing.B) {
+	for _, size := range []int{9, 19} {
+		moves := randomGame(size, size*size, 5)
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				board := eng.NewBoard(size)
+				for _, m := range moves {
+					board, _ = board.ApplyMove(m)
+				}
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*len(moves)), "ns/move")
+		})
+	}
+}
+
+// BenchmarkBoardScore scores the final position of a random game
+func BenchmarkBoardScore(b *testing.B) {
+	for _, size := range []int{9, 19} {
+		board := eng.NewBoard(size)
+		for _, m := range randomGame(size, size*size, 5) {
+			board, _ = board.ApplyMove(m)
+		}
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				board.CalculateChineseScore()
+			}
+		})
+	}
+}
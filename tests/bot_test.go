@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awesohame/gogo/pkg/bot"
+	"github.com/awesohame/gogo/pkg/engine"
+)
+
+// TestBotPlaysLegalMoves tests that the public bot can play a short game against itself
+func TestBotPlaysLegalMoves(t *testing.T) {
+	game := engine.NewGame(5)
+	b := bot.New(bot.Beginner, bot.WithSimulations(100))
+
+	for i := 0; i < 10 && !game.IsGameOver(); i++ {
+		move, err := b.Play(game)
+		if err != nil {
+			t.Fatalf("move %d (%v) failed: %v", i+1, move, err)
+		}
+	}
+	if game.MoveCount() == 0 {
+		t.Error("bot should have placed stones")
+	}
+}
+
+// TestBotAnalyze tests candidate stats and the game over error
+func TestBotAnalyze(t *testing.T) {
+	game := engine.NewGame(5)
+	b := bot.New(bot.Easy, bot.WithSimulations(200))
+
+	analysis, err := b.Analyze(game)
+	if err != nil {
+		t.Fatalf("analyze failed: %v", err)
+	}
+	if analysis.Simulations != 200 {
+		t.Errorf("expected 200 simulations, got %d", analysis.Simulations)
+	}
+	if len(analysis.Candidates) == 0 {
+		t.Fatal("expected candidate moves")
+	}
+	for i := 1; i < len(analysis.Candidates); i++ {
+		if analysis.Candidates[i].Visits > analysis.Candidates[i-1].Visits {
+			t.Fatal("candidates should be sorted by visits")
+		}
+	}
+	if analysis.Best.Color != engine.Black || !game.CurrentBoard().Contains(analysis.Best.Point) {
+		t.Errorf("unexpected best move %v", analysis.Best)
+	}
+
+	game.Resign()
+	if _, err := b.SelectMove(game); !errors.Is(err, bot.ErrGameOver) {
+		t.Errorf("expected ErrGameOver, got %v", err)
+	}
+}
+
+// TestSolveLifeAndDeath tests the public solver on a game set up from a diagram
+func TestSolveLifeAndDeath(t *testing.T) {
+	game, err := engine.NewGameFromDiagram(straightThree, engine.Black)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	solution, err := bot.SolveLifeAndDeath(game, engine.Point{X: 1, Y: 2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solution.Status != bot.Dies || solution.KeyMove.Point != (engine.Point{X: 2, Y: 1}) {
+		t.Errorf("expected black to kill at (2, 1), got %v with %v", solution.Status, solution.KeyMove)
+	}
+
+	if _, err := bot.SolveLifeAndDeath(game, engine.Point{X: 9, Y: 9}, nil); !errors.Is(err, engine.ErrOffBoard) {
+		t.Errorf("expected ErrOffBoard, got %v", err)
+	}
+}
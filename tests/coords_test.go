@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	eng "github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/pkg/engine"
+)
+
+// TestCoordinateFormats tests GTP, SGF and numeric notation both ways
+func TestCoordinateFormats(t *testing.T) {
+	cases := []struct {
+		p   engine.Point
+		gtp string
+		sgf string
+	}{
+		{engine.Point{X: 1, Y: 19}, "A1", "as"},
+		{engine.Point{X: 4, Y: 16}, "D4", "dp"},
+		{engine.Point{X: 9, Y: 1}, "J19", "ia"}, // GTP skips I
+		{engine.Point{X: 19, Y: 19}, "T1", "ss"},
+	}
+	for _, tc := range cases {
+		if got := engine.FormatGTP(tc.p, 19); got != tc.gtp {
+			t.Errorf("%v: expected GTP %s, got %s", tc.p, tc.gtp, got)
+		}
+		if got := engine.FormatSGF(tc.p); got != tc.sgf {
+			t.Errorf("%v: expected SGF %s, got %s", tc.p, tc.sgf, got)
+		}
+		for _, s := range []string{tc.gtp, strings.ToLower(tc.gtp), tc.sgf} {
+			if got, err := engine.ParsePoint(s, 19, 19); err != nil || got != tc.p {
+				t.Errorf("%q: expected %v, got %v (%v)", s, tc.p, got, err)
+			}
+		}
+	}
+
+	if got, err := engine.ParsePoint("4 6", 9, 9); err != nil || got != (engine.Point{X: 4, Y: 6}) {
+		t.Errorf("numeric form: got %v (%v)", got, err)
+	}
+	if got, err := engine.ParsePoint("4,6", 9, 9); err != nil || got != (engine.Point{X: 4, Y: 6}) {
+		t.Errorf("comma form: got %v (%v)", got, err)
+	}
+
+	// rectangular boards count GTP rows from their own bottom edge
+	if got, err := engine.ParsePoint("C1", 7, 5); err != nil || got != (engine.Point{X: 3, Y: 5}) {
+		t.Errorf("7x5 C1: got %v (%v)", got, err)
+	}
+
+	for _, s := range []string{"I5", "Z1", "", "pass", "5", "4 x"} {
+		if _, err := engine.ParsePoint(s, 9, 9); err == nil {
+			t.Errorf("%q should not parse", s)
+		}
+	}
+	for _, s := range []string{"K1", "A10", "jj", "10 1"} {
+		if _, err := engine.ParsePoint(s, 9, 9); !errors.Is(err, engine.ErrOffBoard) {
+			t.Errorf("%q should be off the board, got %v", s, err)
+		}
+	}
+}
+
+// TestGameParseMove tests parsing moves for the side to move
+func TestGameParseMove(t *testing.T) {
+	game := engine.NewGame(9)
+	move, err := game.ParseMove("e5")
+	if err != nil || move != game.NewMove(5, 5, engine.Black) {
+		t.Fatalf("expected black center, got %v (%v)", move, err)
+	}
+	if err := game.MakeMove(move); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	if got := game.FormatMove(move); got != "Black E5" {
+		t.Errorf("unexpected formatted move %q", got)
+	}
+
+	pass, err := game.ParseMove("PASS")
+	if err != nil || !pass.Pass || pass.Color != engine.White {
+		t.Errorf("expected white pass, got %v (%v)", pass, err)
+	}
+}
+
+// TestBoardStringLabels tests the coordinate labels of the board display
+func TestBoardStringLabels(t *testing.T) {
+	board := eng.NewRectBoard(9, 3)
+	board, _ = board.ApplyMove(eng.Move{Point: board.ToPoint(1, 3), Color: eng.Black})
+
+	lines := strings.Split(board.String(), "\n")
+	if strings.TrimSpace(lines[0]) != "A B C D E F G H J" {
+		t.Errorf("unexpected column labels %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], " 3 ") || !strings.HasPrefix(lines[3], " 1 X") {
+		t.Errorf("unexpected row labels:\n%s", board.String())
+	}
+	if got := board.FormatGTP(board.ToPoint(1, 3)); got != "A1" {
+		t.Errorf("expected A1, got %s", got)
+	}
+}
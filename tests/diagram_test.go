@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	eng "github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/pkg/engine"
+)
+
+// TestParseDiagramRoundTrip tests that the output of String parses back to the same position
+func TestParseDiagramRoundTrip(t *testing.T) {
+	board := eng.NewRectBoard(7, 5)
+	rng := rand.New(rand.NewSource(4))
+	color := eng.Black
+	for i := 0; i < 25; i++ {
+		legal := board.LegalMoves(color)
+		if len(legal) == 0 {
+			break
+		}
+		board, _ = board.ApplyMove(eng.Move{Point: legal[rng.Intn(len(legal))], Color: color})
+		if color == eng.Black {
+			color = eng.White
+		} else {
+			color = eng.Black
+		}
+	}
+
+	parsed, err := eng.ParseDiagram(board.String())
+	if err != nil {
+		t.Fatalf("parse failed: %v\n%s", err, board.String())
+	}
+	if parsed.Width() != 7 || parsed.Height() != 5 {
+		t.Fatalf("expected 7x5, got %dx%d", parsed.Width(), parsed.Height())
+	}
+	if parsed.Hash() != board.Hash() || parsed.String() != board.String() {
+		t.Errorf("positions differ:\n%s\n%s", board.String(), parsed.String())
+	}
+	for y := 1; y <= 5; y++ {
+		for x := 1; x <= 7; x++ {
+			p := board.ToPoint(x, y)
+			if parsed.Liberties(p) != board.Liberties(p) {
+				t.Errorf("(%d,%d): expected %d liberties, got %d", x, y, board.Liberties(p), parsed.Liberties(p))
+			}
+		}
+	}
+}
+
+// TestParseDiagramAlternatives tests the alternative characters and frames
+func TestParseDiagramAlternatives(t *testing.T) {
+	board, err := eng.ParseDiagram(`
+		$$ +-------+
+		$$ | # o , |
+		$$ | @ W + |
+		$$ | . . x |
+		$$ +-------+`)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	want, _ := eng.ParseDiagram("XO.\nXO.\n..X")
+	if board.Hash() != want.Hash() || board.Width() != 3 {
+		t.Errorf("unexpected position:\n%s", board.String())
+	}
+
+	bad := map[string]string{
+		"ragged rows":   "...\n..",
+		"unknown char":  "..?\n...",
+		"dead stones":   "XO\nO.",
+		"empty":         "\n\n",
+		"ko not ko":     ".*.\n...\n...",
+		"two ko points": "*X*\nX.X\n...",
+	}
+	for name, diagram := range bad {
+		if _, err := eng.ParseDiagram(diagram); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+// TestGameFromDiagramKo tests side to move and a ko point set up from a diagram
+func TestGameFromDiagramKo(t *testing.T) {
+	game, err := engine.NewGameFromDiagram(`
+		. X O . .
+		X * X O .
+		. X O . .
+		. . . . .
+		. . . . .`, engine.White, engine.WithKomi(0.5))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if game.CurrentTurn() != engine.White || game.Komi() != 0.5 || game.Width() != 5 {
+		t.Errorf("unexpected game: turn %v komi %v width %d", game.CurrentTurn(), game.Komi(), game.Width())
+	}
+	if ko, ok := game.CurrentBoard().KoPoint(); !ok || ko != (engine.Point{X: 2, Y: 2}) {
+		t.Errorf("expected ko at (2,2), got %v %v", ko, ok)
+	}
+
+	// white may not retake until a move elsewhere
+	if err := game.MakeMove(game.NewMove(2, 2, engine.White)); !errors.Is(err, engine.ErrKo) {
+		t.Fatalf("expected ko violation, got %v", err)
+	}
+	for _, m := range []engine.Move{
+		game.NewMove(5, 5, engine.White), game.NewMove(5, 4, engine.Black), game.NewMove(2, 2, engine.White),
+	} {
+		if err := game.MakeMove(m); err != nil {
+			t.Fatalf("move %v failed: %v", m, err)
+		}
+	}
+	if game.CurrentBoard().At(3, 2) != engine.Empty {
+		t.Error("white should have retaken the ko")
+	}
+
+	if _, err := engine.NewGameFromDiagram("...\n...", engine.Black, engine.WithHandicap(2)); err == nil {
+		t.Error("handicap option should be rejected")
+	}
+}
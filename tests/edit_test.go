@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	eng "github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/pkg/engine"
+)
+
+// TestEditPosition tests placing and removing stones outside the rules
+func TestEditPosition(t *testing.T) {
+	game := engine.NewGame(5)
+	if err := game.MakeMove(game.NewMove(3, 3, engine.Black)); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+
+	// three black stones in a row for white to move next, the center stone removed
+	setup := engine.Setup{
+		Black: []engine.Point{{X: 1, Y: 1}, {X: 2, Y: 1}},
+		White: []engine.Point{{X: 1, Y: 2}, {X: 2, Y: 2}},
+		Empty: []engine.Point{{X: 3, Y: 3}},
+	}
+	if err := game.Edit(setup, engine.White); err != nil {
+		t.Fatalf("edit failed: %v", err)
+	}
+	board := game.CurrentBoard()
+	if board.At(3, 3) != engine.Empty || board.At(1, 1) != engine.Black || board.At(2, 2) != engine.White {
+		t.Errorf("edit not applied:\n%s", board)
+	}
+	if game.CurrentTurn() != engine.White {
+		t.Errorf("expected white to move, got %v", game.CurrentTurn())
+	}
+
+	// groups and hash match the same position set up from a diagram
+	want, err := engine.NewGameFromDiagram("XX...\nOO...\n.....\n.....\n.....", engine.White)
+	if err != nil {
+		t.Fatalf("diagram failed: %v", err)
+	}
+	if board.Hash() != want.CurrentBoard().Hash() {
+		t.Error("hash should be recomputed for the edited position")
+	}
+	if group, ok := board.GroupAt(engine.Point{X: 1, Y: 1}); !ok || !group.InAtari() || len(group.Stones) != 2 {
+		t.Errorf("unexpected black group %+v", group)
+	}
+
+	// white captures the edited stones
+	result, err := game.MakeMoveWithResult(game.NewMove(3, 1, engine.White))
+	if err != nil || len(result.Captured) != 2 {
+		t.Fatalf("expected a capture of two, got %+v (%v)", result, err)
+	}
+
+	// undoing the capture and the edit restores board and side to move
+	game.Undo()
+	game.Undo()
+	if game.CurrentTurn() != engine.White || game.CurrentBoard().At(3, 3) != engine.Black {
+		t.Errorf("undo should restore the position before the edit, turn %v\n%s", game.CurrentTurn(), game.CurrentBoard())
+	}
+	if err := game.Redo(); err != nil || game.CurrentTurn() != engine.White || game.CurrentBoard().At(1, 1) != engine.Black {
+		t.Errorf("redo should reapply the edit, turn %v (%v)", game.CurrentTurn(), err)
+	}
+}
+
+// TestEditErrors tests rejected edits
+func TestEditErrors(t *testing.T) {
+	game := engine.NewGame(5)
+	cases := []struct {
+		name  string
+		setup engine.Setup
+	}{
+		{"off board", engine.Setup{Black: []engine.Point{{X: 6, Y: 1}}}},
+		{"listed twice", engine.Setup{Black: []engine.Point{{X: 1, Y: 1}}, White: []engine.Point{{X: 1, Y: 1}}}},
+		{"no liberties", engine.Setup{Black: []engine.Point{{X: 1, Y: 1}}, White: []engine.Point{{X: 2, Y: 1}, {X: 1, Y: 2}}}},
+	}
+	for _, tc := range cases {
+		if err := game.Edit(tc.setup, engine.Black); err == nil {
+			t.Errorf("%s: expected error", tc.name)
+		}
+	}
+	if game.CanUndo() {
+		t.Error("failed edits should not enter the history")
+	}
+
+	board := eng.NewBoard(5)
+	if _, err := board.ApplySetup(eng.Setup{White: []eng.Point{-3}}); !errors.Is(err, eng.ErrOffBoard) {
+		t.Errorf("expected off board, got %v", err)
+	}
+}
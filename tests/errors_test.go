@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	eng "github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/pkg/engine"
+)
+
+// TestMoveErrors tests that each illegal move returns an inspectable error
+func TestMoveErrors(t *testing.T) {
+	game := engine.NewGame(9)
+	moves := []engine.Move{
+		game.NewMove(4, 4, engine.Black), game.NewMove(5, 4, engine.White),
+		game.NewMove(3, 5, engine.Black), game.NewMove(6, 5, engine.White),
+		game.NewMove(4, 6, engine.Black), game.NewMove(5, 6, engine.White),
+		game.NewMove(5, 5, engine.Black), game.NewMove(1, 2, engine.White),
+		game.NewMove(9, 9, engine.Black), game.NewMove(2, 1, engine.White),
+		game.NewMove(9, 8, engine.Black), game.NewMove(4, 5, engine.White), // white takes the ko
+	}
+	for _, m := range moves {
+		if err := game.MakeMove(m); err != nil {
+			t.Fatalf("setup move %v failed: %v", m, err)
+		}
+	}
+
+	cases := []struct {
+		name string
+		move engine.Move
+		want error
+	}{
+		{"occupied", game.NewMove(4, 4, engine.Black), engine.ErrOccupied},
+		{"off board", game.NewMove(10, 1, engine.Black), engine.ErrOffBoard},
+		{"suicide", game.NewMove(1, 1, engine.Black), engine.ErrSuicide},
+		{"ko", game.NewMove(5, 5, engine.Black), engine.ErrKo},
+		{"wrong turn", game.NewMove(7, 7, engine.White), engine.ErrWrongTurn},
+		{"wrong turn pass", engine.PassMove(engine.White), engine.ErrWrongTurn},
+	}
+	for _, tc := range cases {
+		if err := game.MakeMove(tc.move); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	// the ko error names the move whose position would repeat
+	var koErr *engine.KoError
+	if err := game.MakeMove(game.NewMove(5, 5, engine.Black)); !errors.As(err, &koErr) {
+		t.Fatalf("expected a KoError, got %v", err)
+	} else if koErr.MoveNumber != 11 {
+		t.Errorf("expected the position after move 11, got %d", koErr.MoveNumber)
+	}
+
+	game.Resign()
+	if err := game.MakeMove(game.NewMove(7, 7, engine.Black)); !errors.Is(err, engine.ErrGameOver) {
+		t.Errorf("expected game over, got %v", err)
+	}
+	if err := game.Pass(); !errors.Is(err, engine.ErrGameOver) {
+		t.Errorf("expected game over on pass, got %v", err)
+	}
+}
+
+// TestUndoRedoErrors tests the errors at both ends of the history
+func TestUndoRedoErrors(t *testing.T) {
+	game := engine.NewGame(5)
+	if err := game.Undo(); !errors.Is(err, engine.ErrNothingToUndo) {
+		t.Errorf("expected nothing to undo, got %v", err)
+	}
+	if err := game.Redo(); !errors.Is(err, engine.ErrNothingToRedo) {
+		t.Errorf("expected nothing to redo, got %v", err)
+	}
+}
+
+// TestEngineOffBoard tests that points outside the grid are rejected instead of panicking
+func TestEngineOffBoard(t *testing.T) {
+	board := eng.NewBoard(5)
+	for _, p := range []eng.Point{-1, board.ToPoint(0, 1), eng.Point(1000)} {
+		if _, err := board.ApplyMove(eng.Move{Point: p, Color: eng.Black}); !errors.Is(err, eng.ErrOffBoard) {
+			t.Errorf("point %d: expected off board, got %v", p, err)
+		}
+	}
+	for _, l := range []eng.Legality{eng.Occupied, eng.OffBoard, eng.Suicide, eng.KoViolation} {
+		if l.Err() == nil {
+			t.Errorf("%v should map to an error", l)
+		}
+	}
+}
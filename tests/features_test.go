@@ -0,0 +1,188 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	eng "github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/internal/features"
+	"github.com/awesohame/gogo/internal/game"
+	"github.com/awesohame/gogo/internal/selfplay"
+)
+
+// TestFeaturePlanes tests the stone, liberty, ladder and side to move planes
+func TestFeaturePlanes(t *testing.T) {
+	board, err := eng.ParseDiagram(`
+		. . . . . . .
+		. . X X . . .
+		. X O . . . .
+		. . . . . . .
+		. . . . . . .
+		. . . . . . .
+		. . . . . O X`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	planes := features.Extract(board, eng.Black, nil)
+
+	cases := []struct {
+		name  string
+		plane int
+		x, y  int
+		want  float32
+	}{
+		{"own stone", features.PlaneOwn, 3, 2, 1},
+		{"opponent stone", features.PlaneOpponent, 3, 3, 1},
+		{"empty", features.PlaneEmpty, 1, 1, 1},
+		{"stone is not empty", features.PlaneEmpty, 3, 2, 0},
+		{"one liberty", features.PlaneLiberties1, 7, 7, 1},
+		{"two liberties", features.PlaneLiberties2, 3, 3, 1},
+		{"three or more", features.PlaneLiberties3, 3, 2, 1},
+		{"ladder works", features.PlaneLadder, 3, 3, 1},
+		{"group in atari is taken", features.PlaneLadder, 7, 7, 1},
+		{"safe stones", features.PlaneLadder, 6, 7, 0},
+		{"stones with three liberties", features.PlaneLadder, 3, 2, 0},
+		{"black to move", features.PlaneBlackToMove, 5, 5, 1},
+		{"no history", features.PlaneTurnsSince, 3, 2, 0},
+	}
+	for _, tc := range cases {
+		if got := planes.At(tc.plane, tc.x, tc.y); got != tc.want {
+			t.Errorf("%s: expected %v at (%d,%d), got %v", tc.name, tc.want, tc.x, tc.y, got)
+		}
+	}
+
+	// colours are seen from the side to move
+	white := features.Extract(board, eng.White, nil)
+	if white.At(features.PlaneOwn, 3, 3) != 1 || white.At(features.PlaneOpponent, 3, 2) != 1 ||
+		white.At(features.PlaneBlackToMove, 1, 1) != 0 {
+		t.Error("expected the own and opponent planes to swap with white to move")
+	}
+	if len(planes.Data) != features.NumPlanes*7*7 {
+		t.Errorf("expected %d values, got %d", features.NumPlanes*7*7, len(planes.Data))
+	}
+}
+
+// TestFeatureHistory tests the turns-since and ko planes of a session
+func TestFeatureHistory(t *testing.T) {
+	s := game.NewSession(5)
+	moves := [][2]int{{2, 1}, {3, 1}, {1, 2}, {4, 2}, {2, 3}, {3, 3}, {3, 2}, {2, 2}}
+	for _, m := range moves {
+		board := s.CurrentBoard()
+		if err := s.MakeMove(eng.Move{Point: board.ToPoint(m[0], m[1]), Color: s.CurrentTurn()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// white's last move took the black stone at (3,2), black may not retake at once
+	planes := features.FromSession(s)
+
+	// the stone played 2 turns ago was captured, so its plane stays empty
+	marked := map[int][2]int{0: {2, 2}, 2: {3, 3}, 3: {2, 3}}
+	for turn := 0; turn < features.HistoryLength; turn++ {
+		for y := 1; y <= 5; y++ {
+			for x := 1; x <= 5; x++ {
+				want := float32(0)
+				if m, ok := marked[turn]; ok && m == [2]int{x, y} {
+					want = 1
+				}
+				if got := planes.At(features.PlaneTurnsSince+turn, x, y); got != want {
+					t.Errorf("%d turns ago: expected %v at (%d,%d), got %v", turn+1, want, x, y, got)
+				}
+			}
+		}
+	}
+	if planes.At(features.PlaneKo, 3, 2) != 1 {
+		t.Error("expected the ko point at (3,2)")
+	}
+
+	// after black passes white is to move, and white took the ko
+	if err := s.Pass(); err != nil {
+		t.Fatal(err)
+	}
+	if ko := s.KoPoint(); ko != -1 {
+		t.Errorf("expected no ko after a pass, got %s", s.CurrentBoard().FormatGTP(ko))
+	}
+	if planes := features.FromSession(s); planes.At(features.PlaneKo, 3, 2) != 0 {
+		t.Error("expected the ko plane to be empty after a pass")
+	}
+
+	// the board keeps its ko through the pass, search code hands in the ko it tracks instead
+	board := s.CurrentBoard()
+	want := features.FromSession(s)
+	if got := features.ExtractWithKo(board, eng.White, s.RecentBoards(features.HistoryLength), -1); !reflect.DeepEqual(got, want) {
+		t.Error("expected the planes with the ko lifted to match the session's")
+	}
+}
+
+// TestFeatureSymmetry tests that transformed planes match the planes of the transformed board
+func TestFeatureSymmetry(t *testing.T) {
+	board, err := eng.ParseDiagram(`
+		. X . . . . .
+		X O X . . . .
+		. . . . . O .
+		. . . X O . .
+		. . . . . . .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	planes := features.Extract(board, eng.White, nil)
+
+	for _, s := range eng.AllSymmetries {
+		want := features.Extract(board.Transform(s), eng.White, nil)
+		got := planes.Transform(s)
+		if got.Width != want.Width || got.Height != want.Height || !reflect.DeepEqual(got.Data, want.Data) {
+			t.Errorf("%v: transformed planes differ from the planes of the transformed board", s)
+		}
+
+		// a one-hot plane follows TransformIndex
+		index := 2*7 + 5 // (6,3)
+		x, y := index%7+1, index/7+1
+		moved := features.TransformIndex(index, 7, 5, s)
+		if got.Data[features.PlaneOpponent*35+moved] != planes.At(features.PlaneOpponent, x, y) {
+			t.Errorf("%v: expected index %d to follow the planes", s, index)
+		}
+	}
+	if features.TransformIndex(35, 7, 5, eng.Rotate90) != 35 {
+		t.Error("expected a pass to stay a pass")
+	}
+
+	if n := len(features.Augment(planes)); n != 4 {
+		t.Errorf("expected 4 shape-keeping symmetries on 7x5, got %d", n)
+	}
+	if n := len(features.Augment(features.Extract(eng.NewBoard(5), eng.Black, nil))); n != 8 {
+		t.Errorf("expected 8 symmetries on 5x5, got %d", n)
+	}
+}
+
+// TestFeatureSelfPlay tests that stored games give the same planes as the session they were played in
+func TestFeatureSelfPlay(t *testing.T) {
+	s := game.NewSession(5)
+	g := selfplay.Game{Width: 5, Height: 5}
+	for _, m := range [][2]int{{3, 3}, {3, 4}, {4, 4}, {2, 4}, {2, 3}} {
+		board := s.CurrentBoard()
+		stones := make([]eng.Color, 0, 25)
+		for y := 1; y <= 5; y++ {
+			for x := 1; x <= 5; x++ {
+				stones = append(stones, board.At(x, y))
+			}
+		}
+		g.Positions = append(g.Positions, selfplay.Position{ToMove: s.CurrentTurn(), Stones: stones, KoPoint: -1})
+		if err := s.MakeMove(eng.Move{Point: board.ToPoint(m[0], m[1]), Color: s.CurrentTurn()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	last := len(g.Positions) - 1
+	got, err := features.FromSelfPlay(g, last)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if want := features.FromSession(s); !reflect.DeepEqual(got.Data, want.Data) {
+		t.Error("expected the stored game to give the planes of the session")
+	}
+	if _, err := features.FromSelfPlay(g, len(g.Positions)); err == nil {
+		t.Error("expected an error for a position past the end")
+	}
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/awesohame/gogo/internal/ai"
+	eng "github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/pkg/engine"
+)
+
+// TestGroupQueries tests the group and liberty queries of the board view
+func TestGroupQueries(t *testing.T) {
+	game := engine.NewGame(5)
+	moves := []engine.Move{
+		game.NewMove(1, 1, engine.Black), game.NewMove(3, 3, engine.White),
+		game.NewMove(2, 1, engine.Black), game.NewMove(1, 2, engine.White),
+		game.NewMove(4, 4, engine.Black), game.NewMove(2, 2, engine.White),
+	}
+	for _, m := range moves {
+		if err := game.MakeMove(m); err != nil {
+			t.Fatalf("move %v failed: %v", m, err)
+		}
+	}
+	board := game.CurrentBoard()
+
+	group, ok := board.GroupAt(engine.Point{X: 2, Y: 1})
+	if !ok {
+		t.Fatal("expected a group at (2,1)")
+	}
+	if group.Color != engine.Black || len(group.Stones) != 2 || !group.InAtari() {
+		t.Errorf("unexpected group %+v", group)
+	}
+	if group.Liberties[0] != (engine.Point{X: 3, Y: 1}) {
+		t.Errorf("expected liberty at (3,1), got %v", group.Liberties)
+	}
+	if _, ok := board.GroupAt(engine.Point{X: 5, Y: 5}); ok {
+		t.Error("empty point should have no group")
+	}
+	if got := board.Liberties(engine.Point{X: 1, Y: 2}); got != 3 {
+		t.Errorf("expected 3 liberties for white, got %d", got)
+	}
+
+	whites := board.Groups(engine.White)
+	if len(whites) != 2 || len(whites[0].Stones) != 2 || whites[1].Stones[0] != (engine.Point{X: 3, Y: 3}) {
+		t.Errorf("unexpected white groups %+v", whites)
+	}
+	if got := board.GroupsInAtari(engine.Black); len(got) != 1 || len(got[0].Stones) != 2 {
+		t.Errorf("expected the corner group in atari, got %+v", got)
+	}
+	if got := board.GroupsInAtari(engine.White); len(got) != 0 {
+		t.Errorf("expected no white group in atari, got %+v", got)
+	}
+
+	enemies := board.AdjacentEnemyGroups(engine.Point{X: 1, Y: 1})
+	if len(enemies) != 1 || enemies[0].Color != engine.White || len(enemies[0].Stones) != 2 {
+		t.Errorf("expected the white pair as only neighbor, got %+v", enemies)
+	}
+	if got := board.AdjacentEnemyGroups(engine.Point{X: 4, Y: 4}); len(got) != 0 {
+		t.Errorf("expected no enemies next to (4,4), got %+v", got)
+	}
+}
+
+// TestEyeFillingMove tests the eye heuristic on top of the group queries
+func TestEyeFillingMove(t *testing.T) {
+	board := playStones(t, eng.NewBoard(5), []struct {
+		x, y  int
+		color eng.Color
+	}{
+		{1, 1, eng.Black}, {3, 4, eng.White},
+		{3, 1, eng.Black}, {5, 5, eng.White},
+		{2, 2, eng.Black}, {5, 1, eng.White},
+		{2, 3, eng.Black}, {1, 5, eng.White},
+		{4, 3, eng.Black}, {5, 2, eng.White},
+		{3, 2, eng.Black}, {5, 3, eng.White},
+		{2, 4, eng.Black}, {1, 4, eng.White},
+		{4, 4, eng.Black}, {4, 2, eng.White},
+		{3, 5, eng.Black},
+	})
+
+	eye := eng.Move{Point: board.ToPoint(2, 1), Color: eng.Black}
+	if !ai.IsEyeFillingMove(board, eye) {
+		t.Error("(2,1) is a black eye")
+	}
+
+	// (3,3) is surrounded by black, but filling it captures white (3,4)
+	capture := eng.Move{Point: board.ToPoint(3, 3), Color: eng.Black}
+	if ai.IsEyeFillingMove(board, capture) {
+		t.Error("a capturing move is not an eye fill")
+	}
+	if board.Liberties(board.ToPoint(3, 4)) != 1 {
+		t.Fatalf("setup: white (3,4) should be in atari, has %d liberties", board.Liberties(board.ToPoint(3, 4)))
+	}
+}
@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	eng "github.com/awesohame/gogo/internal/engine"
+)
+
+// plays a random game of up to n moves and returns the moves played
+func randomGame(size, n int, seed int64) []eng.Move {
+	rng := rand.New(rand.NewSource(seed))
+	board := eng.NewBoard(size)
+	pb := eng.NewPlayoutBoard(board)
+	moves := make([]eng.Move, 0, n)
+	color := eng.Black
+
+	for len(moves) < n {
+		played := false
+		for attempt := 0; attempt < size*size; attempt++ {
+			p := board.ToPoint(rng.Intn(size)+1, rng.Intn(size)+1)
+			if !pb.IsLegal(p, color) {
+				continue
+			}
+			next, err := board.ApplyMove(eng.Move{Point: p, Color: color})
+			if err != nil {
+				continue
+			}
+			pb.Play(p, color)
+			board = next
+			moves = append(moves, eng.Move{Point: p, Color: color})
+			played = true
+			break
+		}
+		if !played {
+			break
+		}
+		if color == eng.Black {
+			color = eng.White
+		} else {
+			color = eng.Black
+		}
+	}
+	return moves
+}
+
+// TestSuperkoAcrossBranches tests that positions from an abandoned line of play don't count
+func TestSuperkoAcrossBranches(t *testing.T) {
+	root := eng.NewBoard(5)
+	root, _ = root.ApplyMove(eng.Move{Point: root.ToPoint(1, 1), Color: eng.Black})
+
+	// first line of play has white answer at (3,3), then black continues at (4,4)
+	line1, err := root.ApplyMove(eng.Move{Point: root.ToPoint(3, 3), Color: eng.White})
+	if err != nil {
+		t.Fatalf("line 1 failed: %v", err)
+	}
+	if _, err := line1.ApplyMove(eng.Move{Point: root.ToPoint(4, 4), Color: eng.Black}); err != nil {
+		t.Fatalf("line 1 continuation failed: %v", err)
+	}
+
+	// second line branches from root and reaches the same position, which is new for it
+	line2, err := root.ApplyMove(eng.Move{Point: root.ToPoint(3, 3), Color: eng.White})
+	if err != nil {
+		t.Fatalf("branching move rejected: %v", err)
+	}
+	if line2.Hash() != line1.Hash() {
+		t.Errorf("same position should have same hash")
+	}
+
+	// positions from the shared prefix are still remembered on both lines
+	for _, b := range []*eng.Board{line1, line2} {
+		if _, err := b.ApplyMove(eng.Move{Point: b.ToPoint(2, 2), Color: eng.Black}); err != nil {
+			t.Errorf("unrelated move rejected: %v", err)
+		}
+	}
+}
+
+// TestKoInEverySibling tests that every branch from a position keeps the shared history
+func TestKoInEverySibling(t *testing.T) {
+	board, err := eng.ParseDiagram(`
+		. . . . . . .
+		. . X O . . .
+		. X O . O . .
+		. . X O . . .
+		. . . . . . .
+		. . . . . . .
+		. . . . . . .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		// black takes the ko, white may not retake at once in any of the branches
+		child, err := board.ApplyMove(eng.Move{Point: board.ToPoint(4, 3), Color: eng.Black})
+		if err != nil {
+			t.Fatalf("branch %d: %v", i, err)
+		}
+		if got := child.CheckLegality(child.ToPoint(3, 3), eng.White); got != eng.KoViolation {
+			t.Fatalf("branch %d: expected ko, got %v", i, got)
+		}
+		if _, err := child.ApplyMove(eng.Move{Point: child.ToPoint(6, 6), Color: eng.White}); err != nil {
+			t.Fatalf("branch %d: tenuki failed: %v", i, err)
+		}
+	}
+}
+
+// TestIncrementalHashAfterCaptures tests that hashes only depend on the position
+func TestIncrementalHashAfterCaptures(t *testing.T) {
+	// white stone in the corner is captured by two black stones
+	captured := eng.NewBoard(9)
+	captured, _ = captured.ApplyMove(eng.Move{Point: captured.ToPoint(1, 1), Color: eng.White})
+	captured, _ = captured.ApplyMove(eng.Move{Point: captured.ToPoint(2, 1), Color: eng.Black})
+	captured, _ = captured.ApplyMove(eng.Move{Point: captured.ToPoint(1, 2), Color: eng.Black})
+	if captured.At(1, 1) != eng.Empty {
+		t.Fatal("corner stone should have been captured")
+	}
+
+	// same two black stones without a capture
+	direct := eng.NewBoard(9)
+	direct, _ = direct.ApplyMove(eng.Move{Point: direct.ToPoint(1, 2), Color: eng.Black})
+	direct, _ = direct.ApplyMove(eng.Move{Point: direct.ToPoint(2, 1), Color: eng.Black})
+
+	if captured.Hash() != direct.Hash() {
+		t.Errorf("hash %x after capture differs from hash of identical position %x", captured.Hash(), direct.Hash())
+	}
+}
+
+// BenchmarkMoveCostByGameLength reports the avg cost of a move for games of increasing length
+// with incremental hashing and a shared history the cost per move stays flat
+func BenchmarkMoveCostByGameLength(b *testing.B) {
+	for _, length := range []int{50, 200, 800} {
+		moves := randomGame(19, length, 11)
+		b.Run(fmt.Sprintf("moves=%d", len(moves)), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				board := eng.NewBoard(19)
+				for _, m := range moves {
+					board, _ = board.ApplyMove(m)
+				}
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*len(moves)), "ns/move")
+		})
+	}
+}
+
+// BenchmarkBranchCostByGameLength reports the avg cost of expanding every legal move of a position
+// siblings share the history of their parent, so the cost per child does not grow with the game
+func BenchmarkBranchCostByGameLength(b *testing.B) {
+	for _, length := range []int{50, 200, 800} {
+		moves := randomGame(19, length, 11)
+		board := eng.NewBoard(19)
+		for _, m := range moves {
+			board, _ = board.ApplyMove(m)
+		}
+		color := eng.Black
+		if len(moves)%2 == 1 {
+			color = eng.White
+		}
+		children := board.LegalMoves(color)
+		b.Run(fmt.Sprintf("moves=%d", len(moves)), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, p := range children {
+					board.ApplyMove(eng.Move{Point: p, Color: color})
+				}
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*len(children)), "ns/child")
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/awesohame/gogo/internal/ai"
+	eng "github.com/awesohame/gogo/internal/engine"
+)
+
+// white (3,4) is in atari and runs towards the lower right
+const ladderStart = `
+	. . . . . . . . .
+	. . . . . . . . .
+	. . X X . . . . .
+	. X O . . . . . .
+	. . X . . . . . .
+	. . . . . . . . .
+	. . . . . . . . .
+	. . . . . . . %s .
+	. . . . . . . %s .`
+
+// TestLadderReading tests ladders with and without breakers
+func TestLadderReading(t *testing.T) {
+	cases := []struct {
+		name     string
+		diagram  string
+		captured bool
+	}{
+		{"plain ladder", fmt.Sprintf(ladderStart, ".", "."), true},
+		{"white breaker", fmt.Sprintf(ladderStart, "O", "."), false},
+		{"black stone on the path", fmt.Sprintf(ladderStart, ".", "X"), true},
+		{"capture to escape", `
+			. . . . . . . . .
+			. . . . . . . . .
+			. . X X . . . . .
+			O X O . . . . . .
+			. O X . . . . . .
+			. . . . . . . . .
+			. . . . . . . . .
+			. . . . . . . . .
+			. . . . . . . . .`, false},
+	}
+
+	for _, tc := range cases {
+		board, err := eng.ParseDiagram(tc.diagram)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		stone := board.ToPoint(3, 4)
+		if got := board.IsLadderCaptured(stone, eng.White); got != tc.captured {
+			t.Errorf("%s: expected captured=%v, got %v", tc.name, tc.captured, got)
+		}
+
+		// extending is a wasted move exactly when the ladder works
+		extend := eng.Move{Point: board.ToPoint(4, 4), Color: eng.White}
+		if got := ai.IsFutileLadderEscape(board, extend); got != tc.captured {
+			t.Errorf("%s: expected futile escape=%v, got %v", tc.name, tc.captured, got)
+		}
+	}
+}
+
+// TestLadderReadingLeavesBoard tests that reading on a playout board restores it
+func TestLadderReadingLeavesBoard(t *testing.T) {
+	board, err := eng.ParseDiagram(fmt.Sprintf(ladderStart, ".", "."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pb := eng.NewPlayoutBoard(board)
+	hash := pb.Hash()
+
+	// black to move ataris from two liberties after white extends
+	if !pb.Play(pb.ToPoint(4, 4), eng.White) {
+		t.Fatal("extension failed")
+	}
+	if !pb.IsLadderCaptured(pb.ToPoint(3, 4), eng.Black) {
+		t.Error("black should win the chase")
+	}
+	if pb.IsLadderCaptured(pb.ToPoint(3, 4), eng.White) {
+		t.Error("with two liberties and white to move there is no ladder")
+	}
+	pb.Undo()
+	if pb.Hash() != hash || pb.Depth() != 0 {
+		t.Error("ladder reading should leave the board as it was")
+	}
+	if board.IsLadderCaptured(board.ToPoint(5, 5), eng.Black) {
+		t.Error("empty point cannot be captured")
+	}
+}
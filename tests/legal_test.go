@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"testing"
+
+	eng "github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/pkg/engine"
+)
+
+// plays stones given as x, y, color triples, failing the test on an illegal move
+func playStones(t *testing.T, board *eng.Board, stones []struct {
+	x, y  int
+	color eng.Color
+}) *eng.Board {
+	t.Helper()
+	for _, s := range stones {
+		next, err := board.ApplyMove(eng.Move{Point: board.ToPoint(s.x, s.y), Color: s.color})
+		if err != nil {
+			t.Fatalf("setup move (%d,%d) failed: %v", s.x, s.y, err)
+		}
+		board = next
+	}
+	return board
+}
+
+// TestCheckLegality tests each reason a move can be illegal
+func TestCheckLegality(t *testing.T) {
+	board := playStones(t, eng.NewBoard(9), []struct {
+		x, y  int
+		color eng.Color
+	}{
+		{4, 4, eng.Black}, {5, 4, eng.White},
+		{3, 5, eng.Black}, {6, 5, eng.White},
+		{4, 6, eng.Black}, {5, 6, eng.White},
+		{5, 5, eng.Black}, {1, 2, eng.White},
+		{9, 9, eng.Black}, {2, 1, eng.White},
+	})
+
+	cases := []struct {
+		name  string
+		p     eng.Point
+		color eng.Color
+		want  eng.Legality
+	}{
+		{"empty point", board.ToPoint(7, 7), eng.Black, eng.Legal},
+		{"occupied", board.ToPoint(4, 4), eng.White, eng.Occupied},
+		{"border", board.ToPoint(0, 3), eng.Black, eng.OffBoard},
+		{"outside grid", eng.Point(-5), eng.Black, eng.OffBoard},
+		{"suicide in corner", board.ToPoint(1, 1), eng.Black, eng.Suicide},
+		{"own eye", board.ToPoint(1, 1), eng.White, eng.Legal},
+		{"capture", board.ToPoint(4, 5), eng.White, eng.Legal},
+	}
+	for _, tc := range cases {
+		if got := board.CheckLegality(tc.p, tc.color); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+
+	// white takes the ko, black may not retake at once
+	board = playStones(t, board, []struct {
+		x, y  int
+		color eng.Color
+	}{{4, 5, eng.White}})
+	if got := board.CheckLegality(board.ToPoint(5, 5), eng.Black); got != eng.KoViolation {
+		t.Errorf("expected ko, got %v", got)
+	}
+	if _, err := board.ApplyMove(eng.Move{Point: board.ToPoint(5, 5), Color: eng.Black}); err == nil {
+		t.Error("ApplyMove should reject the ko recapture too")
+	}
+}
+
+// TestLegalMovesMatchApplyMove tests the check against actually applying every move
+func TestLegalMovesMatchApplyMove(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		board := eng.NewBoard(7)
+		for i, m := range randomGame(7, 80, seed) {
+			for _, color := range []eng.Color{eng.Black, eng.White} {
+				legal := make(map[eng.Point]bool)
+				for _, p := range board.LegalMoves(color) {
+					legal[p] = true
+				}
+				for y := 1; y <= 7; y++ {
+					for x := 1; x <= 7; x++ {
+						p := board.ToPoint(x, y)
+						_, err := board.ApplyMove(eng.Move{Point: p, Color: color})
+						if legal[p] != (err == nil) {
+							t.Fatalf("seed %d move %d: (%d,%d) legal=%v but ApplyMove err=%v (%v)",
+								seed, i, x, y, legal[p], err, board.CheckLegality(p, color))
+						}
+					}
+				}
+			}
+			board, _ = board.ApplyMove(m)
+		}
+	}
+}
+
+// TestCheckLegalityAllocs tests that checking a move copies no board
+func TestCheckLegalityAllocs(t *testing.T) {
+	board := eng.NewBoard(9)
+	for _, m := range randomGame(9, 40, 3) {
+		board, _ = board.ApplyMove(m)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		for y := 1; y <= 9; y++ {
+			for x := 1; x <= 9; x++ {
+				board.IsLegal(board.ToPoint(x, y), eng.Black)
+			}
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
+
+// TestGameLegality tests the legality queries of the public game
+func TestGameLegality(t *testing.T) {
+	game := engine.NewGame(5)
+	if got := len(game.LegalMoves(engine.Black)); got != 25 {
+		t.Errorf("expected 25 legal moves on an empty board, got %d", got)
+	}
+	if err := game.MakeMove(game.NewMove(3, 3, engine.Black)); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+
+	if game.IsLegal(game.NewMove(3, 3, engine.White)) {
+		t.Error("occupied point should be illegal")
+	}
+	if got := game.CheckLegality(game.NewMove(6, 1, engine.White)); got != engine.OffBoard {
+		t.Errorf("expected off board, got %v", got)
+	}
+	if !game.IsLegal(engine.PassMove(engine.White)) {
+		t.Error("pass should be legal")
+	}
+	for _, p := range game.LegalMoves(engine.White) {
+		if p == (engine.Point{X: 3, Y: 3}) {
+			t.Error("legal moves should skip occupied points")
+		}
+	}
+}
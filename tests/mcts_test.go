@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/awesohame/gogo/internal/ai"
+	eng "github.com/awesohame/gogo/internal/engine"
+)
+
+// TestMCTSPrefersOwnWin tests that root stats are credited to the player making the move
+// so the search picks the move that wins for itself, not the one best for the opponent
+func TestMCTSPrefersOwnWin(t *testing.T) {
+	rows := []string{
+		"..X.X",
+		".XOXX",
+		".X..O",
+		"X.O.O",
+		"...O.",
+	}
+	board := eng.NewBoard(5)
+	for y, row := range rows {
+		for x, c := range row {
+			color := eng.Black
+			if c == 'O' {
+				color = eng.White
+			} else if c != 'X' {
+				continue
+			}
+			next, err := board.ApplyMove(eng.Move{Point: board.ToPoint(x+1, y+1), Color: color})
+			if err != nil {
+				t.Fatal(err)
+			}
+			board = next
+		}
+	}
+
+	// black at (3,3) wins about 9 playouts in 10, every other move loses more than half
+	bot := ai.NewMCTSBot(1000)
+	bot.Verbose = false
+	result := bot.Search(board, eng.Black)
+	if want := board.ToPoint(3, 3); result.Move.Point != want {
+		t.Fatalf("expected black to play at %v, got %v", want, result.Move.Point)
+	}
+	if best := result.Candidates[0]; best.WinRate < 0.75 {
+		t.Errorf("expected the move to win most playouts for black, got win rate %v", best.WinRate)
+	}
+}
+
+// BenchmarkMCTSExpansion measures short searches on a busy 9x9 board, where node expansion
+// classifies every legal point
+func BenchmarkMCTSExpansion(b *testing.B) {
+	rng := rand.New(rand.NewSource(3))
+	board := eng.NewBoard(9)
+	color := eng.Black
+	for placed := 0; placed < 40; {
+		p := board.ToPoint(rng.Intn(9)+1, rng.Intn(9)+1)
+		next, err := board.ApplyMove(eng.Move{Point: p, Color: color})
+		if err != nil {
+			continue
+		}
+		board = next
+		placed++
+		if color == eng.Black {
+			color = eng.White
+		} else {
+			color = eng.Black
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bot := ai.NewMCTSBot(100)
+		bot.Verbose = false
+		bot.ReuseTree = false
+		bot.Search(board, color)
+	}
+}
@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awesohame/gogo/internal/arena"
+	eng "github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/internal/features"
+	"github.com/awesohame/gogo/internal/nn"
+)
+
+// small network used by the tests
+func testNetwork(t *testing.T, width, height int) *nn.Network {
+	t.Helper()
+	net, err := nn.NewNetwork(nn.Config{Width: width, Height: height, Filters: 8, Blocks: 2, ValueHidden: 16}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return net
+}
+
+// TestNetworkOutputs tests that the policy is a distribution over every point and the pass
+func TestNetworkOutputs(t *testing.T) {
+	net := testNetwork(t, 5, 5)
+	board, err := eng.ParseDiagram(`
+		. . . . .
+		. X O . .
+		. . X . .
+		. . . O .
+		. . . . .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy, value, err := net.PolicyValue(board, eng.White, nil, board.KoPoint())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policy) != 26 {
+		t.Fatalf("expected 26 move probabilities, got %d", len(policy))
+	}
+	sum := 0.0
+	for _, p := range policy {
+		if p < 0 {
+			t.Fatalf("negative probability %v", p)
+		}
+		sum += float64(p)
+	}
+	if math.Abs(sum-1) > 1e-4 {
+		t.Errorf("expected probabilities to sum to 1, got %v", sum)
+	}
+	if value < -1 || value > 1 {
+		t.Errorf("expected a value in [-1, 1], got %v", value)
+	}
+}
+
+// TestNetworkSaveLoad tests that a saved network loads back with the same outputs
+func TestNetworkSaveLoad(t *testing.T) {
+	net := testNetwork(t, 7, 5)
+	var buf bytes.Buffer
+	if err := net.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := nn.Load(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Config() != net.Config() {
+		t.Fatalf("expected config %+v, got %+v", net.Config(), loaded.Config())
+	}
+
+	board, err := eng.NewRectBoard(7, 5).ApplyMove(eng.Move{Point: eng.NewRectBoard(7, 5).ToPoint(3, 3), Color: eng.Black})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p1, v1, err := net.PolicyValue(board, eng.White, nil, board.KoPoint())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, v2, err := loaded.PolicyValue(board, eng.White, nil, board.KoPoint())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v1 != v2 {
+		t.Errorf("expected value %v after loading, got %v", v1, v2)
+	}
+	for i := range p1 {
+		if p1[i] != p2[i] {
+			t.Fatalf("expected policy %v at %d after loading, got %v", p1[i], i, p2[i])
+		}
+	}
+
+	// cut files and trailing data are rejected
+	if _, err := nn.Load(bytes.NewReader(buf.Bytes()[:buf.Len()-1])); !errors.Is(err, nn.ErrBadWeights) {
+		t.Errorf("expected ErrBadWeights for a cut file, got %v", err)
+	}
+	if _, err := nn.Load(bytes.NewReader(append(buf.Bytes(), 0))); !errors.Is(err, nn.ErrBadWeights) {
+		t.Errorf("expected ErrBadWeights for trailing data, got %v", err)
+	}
+}
+
+// TestNetworkZeroWeights tests the documented layout with a hand-built file of zero weights
+func TestNetworkZeroWeights(t *testing.T) {
+	const (
+		width, height     = 3, 2
+		filters, blocks   = 2, 1
+		hidden            = 4
+		points            = width * height
+		planes            = features.NumPlanes
+		convIn, convBlock = filters*planes*9 + filters, 2 * (filters*filters*9 + filters)
+		policyHead        = 2*filters + 2 + (points+1)*2*points + points + 1
+		valueHead         = filters + 1 + hidden*points + hidden + hidden + 1
+		floats            = convIn + blocks*convBlock + policyHead + valueHead
+	)
+	header := []byte("GGNN\x01")
+	for _, v := range []uint16{width, height, planes, filters, blocks, hidden} {
+		header = append(header, byte(v), byte(v>>8))
+	}
+	data := append(header, make([]byte, 4*floats)...)
+
+	net, err := nn.Load(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, value, err := net.PolicyValue(eng.NewRectBoard(width, height), eng.Black, nil, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != 0 {
+		t.Errorf("expected value 0, got %v", value)
+	}
+	for i, p := range policy {
+		if math.Abs(float64(p)-1.0/(points+1)) > 1e-6 {
+			t.Fatalf("expected a uniform policy, got %v at %d", p, i)
+		}
+	}
+}
+
+// TestNetworkErrors tests that bad files and mismatched boards are rejected
+func TestNetworkErrors(t *testing.T) {
+	if _, err := nn.Load(bytes.NewReader([]byte("GGSP\x01"))); !errors.Is(err, nn.ErrBadWeights) {
+		t.Errorf("expected ErrBadWeights for another format, got %v", err)
+	}
+	if _, err := nn.NewNetwork(nn.Config{Width: 5, Height: 5}, 1); err == nil {
+		t.Error("expected an error for a network without filters")
+	}
+
+	net := testNetwork(t, 5, 5)
+	if _, _, err := net.PolicyValue(eng.NewBoard(9), eng.Black, nil, -1); err == nil {
+		t.Error("expected an error for a board of the wrong size")
+	}
+	var data bytes.Buffer
+	if err := net.Save(&data); err != nil {
+		t.Fatal(err)
+	}
+	data.Bytes()[4] = 9 // version
+	if _, err := nn.Load(&data); !errors.Is(err, nn.ErrBadWeights) {
+		t.Errorf("expected ErrBadWeights for an unknown version, got %v", err)
+	}
+}
+
+// TestArenaPUCTPlayer tests that a puct spec loads its weight file
+func TestArenaPUCTPlayer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "net.bin")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testNetwork(t, 5, 5).Save(f); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	player, err := arena.ParsePlayer("puct:net="+path+",sims=20,blend=0.5", 0, 7.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	board := eng.NewBoard(5)
+	if move := player.New(0).SelectMove(board, eng.Black); move.Point >= 0 && !board.IsLegal(move.Point, eng.Black) {
+		t.Errorf("expected a legal move, got %v", move)
+	}
+	if _, err := arena.ParsePlayer("puct:sims=20", 0, 7.5); err == nil {
+		t.Error("expected an error for a puct spec without a net")
+	}
+}
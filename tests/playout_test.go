@@ -0,0 +1,199 @@
+package tests
+
+import (
+	"math/rand"
+	"testing"
+
+	eng "github.com/awesohame/gogo/internal/engine"
+)
+
+// checks that a playout board holds the same stones and hash as a board
+func samePosition(t *testing.T, step int, b *eng.Board, pb *eng.PlayoutBoard) {
+	t.Helper()
+	for y := 1; y <= b.Size(); y++ {
+		for x := 1; x <= b.Size(); x++ {
+			if got, want := pb.ColorAt(pb.ToPoint(x, y)), b.At(x, y); got != want {
+				t.Fatalf("step %d: (%d,%d) = %v, want %v\nBoard:\n%s", step, x, y, got, want, b.String())
+			}
+		}
+	}
+	if pb.Hash() != b.Hash() {
+		t.Fatalf("step %d: hash %x, want %x", step, pb.Hash(), b.Hash())
+	}
+}
+
+// TestPlayoutBoardMatchesBoard plays random games on both boards and compares them
+func TestPlayoutBoardMatchesBoard(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+
+	for game := 0; game < 20; game++ {
+		board := eng.NewBoard(7)
+		pb := eng.NewPlayoutBoard(board)
+		history := []*eng.Board{board}
+		color := eng.Black
+
+		for step := 0; step < 200; step++ {
+			x, y := rng.Intn(7)+1, rng.Intn(7)+1
+			p := board.ToPoint(x, y)
+			move := eng.Move{Point: p, Color: color}
+
+			next, err := board.ApplyMove(move)
+			legal := pb.IsLegal(p, color)
+			if err == nil && !legal {
+				t.Fatalf("game %d step %d: playout board rejected legal move (%d,%d)", game, step, x, y)
+			}
+			if err != nil {
+				// superko may reject moves that simple ko allows
+				continue
+			}
+
+			if !pb.Play(p, color) {
+				t.Fatalf("game %d step %d: Play failed", game, step)
+			}
+			board = next
+			history = append(history, board)
+			samePosition(t, step, board, pb)
+
+			// occasionally take back a few moves and replay them
+			if step%17 == 16 {
+				depth := len(history) - 1
+				back := min(3, depth)
+				for i := 0; i < back; i++ {
+					if !pb.Undo() {
+						t.Fatalf("game %d step %d: Undo failed", game, step)
+					}
+					samePosition(t, step, history[depth-i-1], pb)
+				}
+				pb = eng.NewPlayoutBoard(board)
+			}
+
+			if color == eng.Black {
+				color = eng.White
+			} else {
+				color = eng.Black
+			}
+		}
+	}
+}
+
+// TestPlayoutBoardKo tests simple ko in place
+func TestPlayoutBoardKo(t *testing.T) {
+	pb := eng.NewPlayoutBoard(eng.NewBoard(9))
+	moves := []struct {
+		x, y  int
+		color eng.Color
+	}{
+		{4, 4, eng.Black}, {5, 4, eng.White},
+		{3, 5, eng.Black}, {6, 5, eng.White},
+		{4, 6, eng.Black}, {5, 6, eng.White},
+		{5, 5, eng.Black},
+	}
+	for _, m := range moves {
+		if !pb.Play(pb.ToPoint(m.x, m.y), m.color) {
+			t.Fatalf("setup move (%d,%d) failed", m.x, m.y)
+		}
+	}
+
+	// white captures at (4,5)
+	if !pb.Play(pb.ToPoint(4, 5), eng.White) {
+		t.Fatal("capture failed")
+	}
+	if got := pb.LastCaptures(); len(got) != 1 || got[0] != pb.ToPoint(5, 5) {
+		t.Fatalf("expected capture at (5,5), got %v", got)
+	}
+
+	// black may not retake immediately
+	if pb.IsLegal(pb.ToPoint(5, 5), eng.Black) {
+		t.Error("ko recapture should be illegal")
+	}
+
+	// undo restores the captured stone
+	pb.Undo()
+	if pb.ColorAt(pb.ToPoint(5, 5)) != eng.Black {
+		t.Error("undo should restore captured stone")
+	}
+	if pb.Liberties(pb.ToPoint(5, 5)) != 1 {
+		t.Errorf("expected 1 liberty after undo, got %d", pb.Liberties(pb.ToPoint(5, 5)))
+	}
+}
+
+// TestPlayoutBoardKoFromBoard tests that a ko copied from a board only bans the retake
+func TestPlayoutBoardKoFromBoard(t *testing.T) {
+	board := eng.NewBoard(5)
+	moves := []struct {
+		x, y  int
+		color eng.Color
+	}{
+		{2, 1, eng.Black}, {3, 1, eng.White},
+		{1, 2, eng.Black}, {2, 2, eng.White},
+		{2, 3, eng.Black}, {4, 2, eng.White},
+		{5, 5, eng.Black}, {3, 3, eng.White},
+		{3, 2, eng.Black}, // takes the white stone at (2,2)
+	}
+	for _, m := range moves {
+		next, err := board.ApplyMove(eng.Move{Point: board.ToPoint(m.x, m.y), Color: m.color})
+		if err != nil {
+			t.Fatalf("setup move (%d,%d) failed: %v", m.x, m.y, err)
+		}
+		board = next
+	}
+	ko := board.ToPoint(2, 2)
+	if board.KoPoint() != ko {
+		t.Fatalf("expected a ko at (2,2), got %v", board.KoPoint())
+	}
+
+	// as if white passed, black who took the ko may fill it
+	pb := eng.NewPlayoutBoard(board)
+	if pb.IsLegal(ko, eng.White) {
+		t.Error("expected white's retake to be illegal")
+	}
+	if !pb.IsLegal(ko, eng.Black) {
+		t.Error("expected black to be allowed to fill the ko")
+	}
+	pb.Pass(eng.White)
+	pb.Undo()
+	if pb.IsLegal(ko, eng.White) {
+		t.Error("expected undo to restore the ban on white")
+	}
+}
+
+// BenchmarkPlayoutBoard measures random moves on the in-place board
+func BenchmarkPlayoutBoard(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	start := eng.NewBoard(9)
+	for i := 0; i < b.N; i++ {
+		pb := eng.NewPlayoutBoard(start)
+		color := eng.Black
+		for j := 0; j < 100; j++ {
+			p := pb.ToPoint(rng.Intn(9)+1, rng.Intn(9)+1)
+			if pb.Play(p, color) {
+				if color == eng.Black {
+					color = eng.White
+				} else {
+					color = eng.Black
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkApplyMove measures the same random moves with board copies
+func BenchmarkApplyMove(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	start := eng.NewBoard(9)
+	for i := 0; i < b.N; i++ {
+		board := start
+		color := eng.Black
+		for j := 0; j < 100; j++ {
+			p := board.ToPoint(rng.Intn(9)+1, rng.Intn(9)+1)
+			if next, err := board.ApplyMove(eng.Move{Point: p, Color: color}); err == nil {
+				board = next
+				if color == eng.Black {
+					color = eng.White
+				} else {
+					color = eng.Black
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/awesohame/gogo/internal/ai"
+	eng "github.com/awesohame/gogo/internal/engine"
+)
+
+// net that puts all of its prior on one point, on the pass for x -1, or a uniform one for x 0, or fails
+type fixedNet struct {
+	x, y      int
+	value     float32
+	err       error
+	calls     int
+	histories []int       // no. of earlier positions handed in on each call
+	kos       []eng.Point // ko point handed in on each call
+}
+
+func (n *fixedNet) PolicyValue(board *eng.Board, _ eng.Color, history []*eng.Board, ko eng.Point) ([]float32, float32, error) {
+	n.calls++
+	n.histories = append(n.histories, len(history))
+	n.kos = append(n.kos, ko)
+	if n.err != nil {
+		return nil, 0, n.err
+	}
+	policy := make([]float32, board.Width()*board.Height()+1)
+	if n.x == 0 {
+		for i := range policy {
+			policy[i] = 1 / float32(len(policy))
+		}
+	} else if n.x < 0 {
+		policy[len(policy)-1] = 1
+	} else {
+		policy[(n.y-1)*board.Width()+n.x-1] = 1
+	}
+	return policy, n.value, nil
+}
+
+// TestPUCTFollowsPrior tests that the search spends its visits on the move the policy prefers
+func TestPUCTFollowsPrior(t *testing.T) {
+	net := &fixedNet{x: 2, y: 4}
+	bot := ai.NewPUCTBot(net, 50)
+	board := eng.NewBoard(5)
+
+	result := bot.Search(board, eng.Black)
+	if want := board.ToPoint(2, 4); result.Move.Point != want {
+		t.Fatalf("expected the prior move %v, got %v", want, result.Move.Point)
+	}
+	if result.Simulations != 50 || net.calls > 51 {
+		t.Errorf("expected 50 simulations and at most 51 net calls, got %d and %d", result.Simulations, net.calls)
+	}
+	total := 0
+	for _, c := range result.Candidates {
+		total += c.Visits
+		if c.WinRate < 0 || c.WinRate > 1 {
+			t.Errorf("expected win rates in [0, 1], got %v", c.WinRate)
+		}
+	}
+	if total != 50 {
+		t.Errorf("expected 50 root visits, got %d", total)
+	}
+}
+
+// TestPUCTPlayouts tests that blended playouts find a capture the net knows nothing about
+func TestPUCTPlayouts(t *testing.T) {
+	board, err := eng.ParseDiagram(`
+		. X X X .
+		X O O O .
+		. X X X .
+		. . . . .
+		. . . . .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the three white stones have one liberty left
+	search := func() ai.SearchResult {
+		bot := ai.NewPUCTBot(&fixedNet{}, 1000)
+		bot.ValueWeight = 0 // playouts only
+		bot.Rand = rand.New(rand.NewSource(1))
+		return bot.Search(board, eng.Black)
+	}
+	result := search()
+	if move := result.Move; move.Point != board.ToPoint(5, 2) {
+		t.Errorf("expected black to capture at %s, got %s", board.FormatGTP(board.ToPoint(5, 2)), board.FormatGTP(move.Point))
+	}
+
+	// the same seed plays the same playouts
+	again := search()
+	for i, c := range result.Candidates {
+		if again.Candidates[i] != c {
+			t.Fatalf("expected the same search from the same seed, got %+v and %+v", c, again.Candidates[i])
+		}
+	}
+}
+
+// TestPUCTFallback tests that a failing net falls back to a playout search
+func TestPUCTFallback(t *testing.T) {
+	bot := ai.NewPUCTBot(&fixedNet{err: errors.New("no net")}, 30)
+	board := eng.NewBoard(5)
+	move := bot.SelectMove(board, eng.White)
+	if move.Point < 0 || !board.IsLegal(move.Point, eng.White) {
+		t.Errorf("expected a legal move, got %v", move)
+	}
+}
+
+// TestPUCTPassesEndGame tests that two passes are scored with the komi
+func TestPUCTPassesEndGame(t *testing.T) {
+	// white owns the whole board and has nothing left but to fill its own eyes
+	board, err := eng.ParseDiagram(`
+		O . O
+		O O O
+		O . O`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot := ai.NewPUCTBot(&fixedNet{x: 1, y: 1}, 40)
+	result := bot.Search(board, eng.White)
+	if result.Move.Point >= 0 {
+		t.Fatalf("expected white to pass with only own eyes left, got %s", board.FormatGTP(result.Move.Point))
+	}
+	// the first visit is the net value 0, every later one ends the game in white's favour
+	if c := result.Candidates[0]; c.WinRate < 0.95 {
+		t.Errorf("expected passing to win for white, got win rate %v", c.WinRate)
+	}
+}
+
+// TestPUCTPlayoutKomi tests that playouts are scored with the bot's komi
+func TestPUCTPlayoutKomi(t *testing.T) {
+	// black owns all 9 points with two eyes, white can only pass and so can black
+	board, err := eng.ParseDiagram(`
+		X . X
+		X X X
+		X . X`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct{ komi, want float64 }{{7.5, 0}, {9.5, 1}} {
+		bot := ai.NewPUCTBot(&fixedNet{}, 1)
+		bot.ValueWeight = 0 // playouts only
+		bot.Komi = c.komi
+		// the one simulation plays out the position after white's pass
+		pass := bot.Search(board, eng.White).Candidates[0]
+		if pass.Visits != 1 || pass.WinRate != c.want {
+			t.Errorf("komi %v: expected white's pass to win %v of 1 playout, got %v of %d", c.komi, c.want, pass.WinRate, pass.Visits)
+		}
+	}
+}
+
+// TestPUCTHistory tests that the net sees the game before the root
+func TestPUCTHistory(t *testing.T) {
+	board := eng.NewBoard(5)
+	history := []*eng.Board{board, board, board}
+	net := &fixedNet{x: 3, y: 3}
+	ai.NewPUCTBot(net, 1).SearchWithHistory(board, eng.Black, history)
+	// the root, then the position after black's move at (3,3)
+	if len(net.histories) != 2 || net.histories[0] != 3 || net.histories[1] != 4 {
+		t.Errorf("expected 3 earlier positions at the root and 4 after a move, got %v", net.histories)
+	}
+}
+
+// TestPUCTKoAfterPass tests that the net sees no ko once a pass in the tree lifts it
+func TestPUCTKoAfterPass(t *testing.T) {
+	board, err := eng.ParseDiagram(`
+		. X O . .
+		X O . O .
+		. X O . .
+		. . . . .
+		. . . . .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// black takes at (3,2), white may not retake at (2,2) right away
+	board, err = board.ApplyMove(eng.Move{Point: board.ToPoint(3, 2), Color: eng.Black})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ko := board.ToPoint(2, 2)
+	if board.KoPoint() != ko {
+		t.Fatalf("expected a ko at (2,2), got %v", board.KoPoint())
+	}
+
+	// all prior on the pass, so the one simulation expands white's pass
+	net := &fixedNet{x: -1}
+	ai.NewPUCTBot(net, 1).Search(board, eng.White)
+	if len(net.kos) != 2 || net.kos[0] != ko || net.kos[1] != -1 {
+		t.Errorf("expected the ko at the root and none after the pass, got %v", net.kos)
+	}
+}
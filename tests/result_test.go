@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/awesohame/gogo/pkg/engine"
+)
+
+// TestMoveResultCaptures tests captured points and liberties of the placed group
+func TestMoveResultCaptures(t *testing.T) {
+	game := engine.NewGame(5)
+	moves := []engine.Move{
+		game.NewMove(1, 1, engine.Black), game.NewMove(1, 2, engine.White),
+		game.NewMove(2, 1, engine.Black), game.NewMove(2, 2, engine.White),
+	}
+	for _, m := range moves {
+		result, err := game.MakeMoveWithResult(m)
+		if err != nil {
+			t.Fatalf("move %v failed: %v", m, err)
+		}
+		if len(result.Captured) != 0 || result.HasKo() {
+			t.Errorf("move %v should not capture, got %+v", m, result)
+		}
+	}
+
+	result, err := game.MakeMoveWithResult(game.NewMove(4, 4, engine.Black))
+	if err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	if result.Liberties != 4 {
+		t.Errorf("expected 4 liberties, got %d", result.Liberties)
+	}
+
+	// white (3,1) takes both black stones
+	result, err = game.MakeMoveWithResult(game.NewMove(3, 1, engine.White))
+	if err != nil {
+		t.Fatalf("capture failed: %v", err)
+	}
+	want := []engine.Point{{X: 1, Y: 1}, {X: 2, Y: 1}}
+	if len(result.Captured) != 2 || result.Captured[0] != want[0] || result.Captured[1] != want[1] {
+		t.Errorf("expected captures %v, got %v", want, result.Captured)
+	}
+	if result.HasKo() {
+		t.Errorf("capturing two stones is not a ko, got %v", result.KoPoint)
+	}
+	// (3,1) is not connected to the other white stones
+	if result.Liberties != 3 {
+		t.Errorf("expected 3 liberties, got %d", result.Liberties)
+	}
+
+	// passes return an empty result
+	if result, err := game.MakeMoveWithResult(engine.PassMove(engine.Black)); err != nil || result.Liberties != 0 {
+		t.Errorf("unexpected pass result %+v, %v", result, err)
+	}
+}
+
+// TestMoveResultKo tests the ko point of a single stone capture
+func TestMoveResultKo(t *testing.T) {
+	game := engine.NewGame(9)
+	moves := []engine.Move{
+		game.NewMove(4, 4, engine.Black), game.NewMove(5, 4, engine.White),
+		game.NewMove(3, 5, engine.Black), game.NewMove(6, 5, engine.White),
+		game.NewMove(4, 6, engine.Black), game.NewMove(5, 6, engine.White),
+		game.NewMove(5, 5, engine.Black),
+	}
+	for _, m := range moves {
+		if err := game.MakeMove(m); err != nil {
+			t.Fatalf("setup move %v failed: %v", m, err)
+		}
+	}
+
+	result, err := game.MakeMoveWithResult(game.NewMove(4, 5, engine.White))
+	if err != nil {
+		t.Fatalf("ko capture failed: %v", err)
+	}
+	ko := engine.Point{X: 5, Y: 5}
+	if len(result.Captured) != 1 || result.Captured[0] != ko {
+		t.Errorf("expected capture at %v, got %v", ko, result.Captured)
+	}
+	if !result.HasKo() || result.KoPoint != ko {
+		t.Errorf("expected ko at %v, got %v", ko, result.KoPoint)
+	}
+	if result.Liberties != 1 {
+		t.Errorf("expected the capturing stone in atari, got %d liberties", result.Liberties)
+	}
+}
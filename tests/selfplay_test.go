@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	eng "github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/internal/selfplay"
+)
+
+// TestSelfPlayFormat tests that games read back as written, also after appending to a file
+func TestSelfPlayFormat(t *testing.T) {
+	stones := make([]eng.Color, 5*3)
+	stones[0], stones[7], stones[14] = eng.Black, eng.White, eng.Black
+	games := []selfplay.Game{
+		{
+			Width: 5, Height: 3, Komi: 7.5, Winner: eng.White, Score: -2.5,
+			Positions: []selfplay.Position{
+				{ToMove: eng.White, Stones: stones, KoPoint: 6, Move: 15,
+					Visits: []selfplay.Visit{{Move: 15, Count: 70000}, {Move: 3, Count: 1}}},
+				{ToMove: eng.Black, Stones: make([]eng.Color, 15), KoPoint: -1, Move: 2},
+			},
+		},
+		{Width: 2, Height: 2, Winner: eng.Empty},
+	}
+
+	var buf bytes.Buffer
+	w, err := selfplay.NewWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteGame(games[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	appended := selfplay.AppendWriter(&buf)
+	if err := appended.WriteGame(games[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := appended.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := selfplay.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range games {
+		got, err := r.ReadGame()
+		if err != nil {
+			t.Fatalf("game %d: %v", i, err)
+		}
+		if got.Width != want.Width || got.Height != want.Height || got.Komi != want.Komi ||
+			got.Winner != want.Winner || got.Score != want.Score || len(got.Positions) != len(want.Positions) {
+			t.Fatalf("game %d: expected %+v, got %+v", i, want, got)
+		}
+		for j, p := range want.Positions {
+			q := got.Positions[j]
+			if q.ToMove != p.ToMove || q.KoPoint != p.KoPoint || q.Move != p.Move ||
+				!reflect.DeepEqual(q.Stones, p.Stones) || len(q.Visits) != len(p.Visits) ||
+				(len(p.Visits) > 0 && !reflect.DeepEqual(q.Visits, p.Visits)) {
+				t.Errorf("game %d position %d: expected %+v, got %+v", i, j, p, q)
+			}
+		}
+	}
+	if _, err := r.ReadGame(); err != io.EOF {
+		t.Errorf("expected io.EOF after the last game, got %v", err)
+	}
+}
+
+// TestSelfPlayBadFile tests that other files and cut off games are rejected
+func TestSelfPlayBadFile(t *testing.T) {
+	if _, err := selfplay.NewReader(bytes.NewReader([]byte("(;GM[1]"))); !errors.Is(err, selfplay.ErrBadFormat) {
+		t.Errorf("expected ErrBadFormat for an SGF file, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	w, _ := selfplay.NewWriter(&buf)
+	w.WriteGame(selfplay.Game{Width: 3, Height: 3, Positions: []selfplay.Position{
+		{ToMove: eng.Black, Stones: make([]eng.Color, 9), KoPoint: -1, Move: 4},
+	}})
+	w.Flush()
+	r, err := selfplay.NewReader(bytes.NewReader(buf.Bytes()[:buf.Len()-3]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadGame(); !errors.Is(err, selfplay.ErrBadFormat) {
+		t.Errorf("expected ErrBadFormat for a truncated game, got %v", err)
+	}
+
+	if err := w.WriteGame(selfplay.Game{Width: 3, Height: 3, Positions: []selfplay.Position{{Stones: make([]eng.Color, 4)}}}); err == nil {
+		t.Error("expected an error for a position of the wrong size")
+	}
+}
+
+// TestSelfPlayGenerate tests that generated positions are consistent with their games
+func TestSelfPlayGenerate(t *testing.T) {
+	cfg := selfplay.Config{
+		Width: 5, Games: 2, Simulations: 40, Komi: 0.5,
+		OpeningMoves: 3, Temperature: 1, TemperatureMoves: 4, Concurrency: 2, Seed: 1,
+	}
+	var games []selfplay.Game
+	err := selfplay.Generate(cfg, func(g selfplay.Game) error {
+		games = append(games, g)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 2 {
+		t.Fatalf("expected 2 games, got %d", len(games))
+	}
+
+	for i, g := range games {
+		if g.Width != 5 || g.Height != 5 || len(g.Positions) == 0 {
+			t.Fatalf("game %d: expected searched positions on 5x5, got %dx%d with %d", i, g.Width, g.Height, len(g.Positions))
+		}
+		if g.Winner == eng.Empty {
+			t.Errorf("game %d: expected a winner with half a point of komi", i)
+		}
+		for j, p := range g.Positions {
+			if len(p.Stones) != 25 || p.Move < 0 || p.Move > 25 {
+				t.Fatalf("game %d position %d: bad position %+v", i, j, p)
+			}
+			total, played := 0, p.Move == 25
+			for _, v := range p.Visits {
+				total += v.Count
+				if v.Move == p.Move {
+					played = true
+				}
+				if v.Move < 25 && p.Stones[v.Move] != eng.Empty {
+					t.Errorf("game %d position %d: visits on the occupied point %d", i, j, v.Move)
+				}
+			}
+			if total == 0 || !played {
+				t.Errorf("game %d position %d: expected visits including the move played, got %+v", i, j, p.Visits)
+			}
+			if j > 0 && g.Positions[j-1].ToMove == p.ToMove {
+				t.Errorf("game %d position %d: expected the side to move to alternate", i, j)
+			}
+		}
+	}
+}
+
+// TestSelfPlaySymmetricMoves tests that moves merged by symmetry are played anywhere in their orbit
+func TestSelfPlaySymmetricMoves(t *testing.T) {
+	// every point of the empty 2x2 board is the same move, the search keeps only one of them
+	cfg := selfplay.Config{Width: 2, Games: 16, Simulations: 20, MaxMoves: 1, Seed: 1}
+	first := make(map[int]bool)
+	err := selfplay.Generate(cfg, func(g selfplay.Game) error {
+		if len(g.Positions) > 0 && g.Positions[0].Move < 4 {
+			first[g.Positions[0].Move] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) < 2 {
+		t.Errorf("expected the first moves to spread over the board, got only %v", first)
+	}
+}
+
+// TestSelfPlayEmitError tests that an error from emit stops the run
+func TestSelfPlayEmitError(t *testing.T) {
+	stop := errors.New("disk full")
+	calls := 0
+	err := selfplay.Generate(selfplay.Config{Width: 4, Games: 5, Simulations: 10}, func(selfplay.Game) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("expected the first error after one game, got %v after %d", err, calls)
+	}
+}
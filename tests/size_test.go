@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"testing"
+
+	eng "github.com/awesohame/gogo/internal/engine"
+	"github.com/awesohame/gogo/pkg/engine"
+)
+
+// TestLargeBoards tests sizes above 19x19, which used to overflow the hash table
+func TestLargeBoards(t *testing.T) {
+	for _, size := range []int{21, 23, 25} {
+		game := engine.NewGame(size)
+
+		// capture in the far corner
+		moves := []struct {
+			x, y  int
+			color eng.Color
+		}{
+			{1, 1, eng.Black},
+			{size, size, eng.White},
+			{size - 1, size, eng.Black},
+			{2, 2, eng.White},
+			{size, size - 1, eng.Black},
+		}
+		for i, m := range moves {
+			if err := game.MakeMove(game.NewMove(m.x, m.y, m.color)); err != nil {
+				t.Fatalf("%dx%d move %d failed: %v", size, size, i+1, err)
+			}
+		}
+
+		board := game.CurrentBoard()
+		if board.At(size, size) != eng.Empty {
+			t.Errorf("%dx%d: corner stone should be captured", size, size)
+		}
+
+		score := game.GetScore()
+		if total := score.Black + score.White + score.DamePoints; total != size*size {
+			t.Errorf("%dx%d: score covers %d points, want %d", size, size, total, size*size)
+		}
+	}
+}
+
+// TestRectangularBoard tests play and scoring on a non-square board
+func TestRectangularBoard(t *testing.T) {
+	game := engine.NewRectGame(13, 7)
+	if game.Width() != 13 || game.Height() != 7 {
+		t.Fatalf("expected 13x7, got %dx%d", game.Width(), game.Height())
+	}
+
+	// black walls off the left 3 columns
+	for y := 1; y <= 7; y++ {
+		if err := game.MakeMove(game.NewMove(4, y, eng.Black)); err != nil {
+			t.Fatalf("black move at (4,%d) failed: %v", y, err)
+		}
+		if err := game.MakeMove(game.NewMove(13, y, eng.White)); err != nil {
+			t.Fatalf("white move at (13,%d) failed: %v", y, err)
+		}
+	}
+
+	score := game.GetScore()
+	if score.BlackStones != 7 || score.WhiteStones != 7 {
+		t.Errorf("expected 7 stones each, got %+v", score)
+	}
+	if score.BlackArea != 21 {
+		t.Errorf("expected 21 points of black territory, got %d", score.BlackArea)
+	}
+	if total := score.Black + score.White + score.DamePoints; total != 13*7 {
+		t.Errorf("score covers %d points, want %d", total, 13*7)
+	}
+
+	// bottom right corner is a real point, one past it is not
+	board := game.CurrentBoard()
+	if board.At(13, 7) != eng.White {
+		t.Errorf("expected white at (13,7), got %v", board.At(13, 7))
+	}
+	if board.At(14, 7) != eng.Border || board.At(13, 8) != eng.Border {
+		t.Error("points past the edge should be border")
+	}
+}
+
+// TestTinyBoard tests the smallest supported board
+func TestTinyBoard(t *testing.T) {
+	game := engine.NewGame(2)
+
+	if err := game.MakeMove(game.NewMove(1, 1, eng.Black)); err != nil {
+		t.Fatalf("move 1 failed: %v", err)
+	}
+	if err := game.MakeMove(game.NewMove(2, 2, eng.White)); err != nil {
+		t.Fatalf("move 2 failed: %v", err)
+	}
+
+	if err := game.MakeMove(game.NewMove(2, 1, eng.Black)); err != nil {
+		t.Fatalf("move 3 failed: %v", err)
+	}
+
+	// white fills the shared last liberty and captures both black stones
+	if err := game.MakeMove(game.NewMove(1, 2, eng.White)); err != nil {
+		t.Fatalf("move 4 failed: %v", err)
+	}
+
+	board := game.CurrentBoard()
+	if board.At(1, 1) != eng.Empty || board.At(2, 1) != eng.Empty {
+		t.Errorf("black stones should be captured\n%s", board.String())
+	}
+
+	score := game.GetScore()
+	if score.White != 4 {
+		t.Errorf("white should own the whole board, got %+v", score)
+	}
+}
+
+// TestBoardSizeValidation tests parsing and limits of board sizes
+func TestBoardSizeValidation(t *testing.T) {
+	valid := map[string][2]int{"9": {9, 9}, "19x13": {19, 13}, " 25X25 ": {25, 25}, "2x3": {2, 3}}
+	for in, want := range valid {
+		w, h, err := eng.ParseBoardSize(in)
+		if err != nil || w != want[0] || h != want[1] {
+			t.Errorf("ParseBoardSize(%q) = %d, %d, %v; want %v", in, w, h, err, want)
+		}
+	}
+
+	for _, in := range []string{"1", "26", "19x26", "abc", "9x"} {
+		if _, _, err := eng.ParseBoardSize(in); err == nil {
+			t.Errorf("ParseBoardSize(%q) should fail", in)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewRectBoard(26, 9) should panic")
+		}
+	}()
+	eng.NewRectBoard(26, 9)
+}
@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/awesohame/gogo/internal/ai"
+	eng "github.com/awesohame/gogo/internal/engine"
+)
+
+// TestExactSolverTwoByTwo tests the known value of the 2x2 board
+func TestExactSolverTwoByTwo(t *testing.T) {
+	result, err := ai.NewExactSolver(0).Solve(eng.NewRectBoard(2, 2), eng.Black)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Exact || result.Score != 1 {
+		t.Errorf("expected an exact score of 1, got %v (exact=%v)", result.Score, result.Exact)
+	}
+	if len(result.PV) == 0 || result.PV[0].Color != eng.Black {
+		t.Errorf("expected a principal variation starting with black, got %v", result.PV)
+	}
+}
+
+// TestExactSolverKomi tests that komi shifts the score and that white to move sees the same game
+func TestExactSolverKomi(t *testing.T) {
+	board := eng.NewRectBoard(2, 2)
+	result, err := ai.NewExactSolver(0.5).Solve(board, eng.Black)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Exact || result.Score != 0.5 {
+		t.Errorf("expected an exact score of 0.5, got %v (exact=%v)", result.Score, result.Exact)
+	}
+
+	// the same game with colours swapped, white wins by one minus komi
+	result, err = ai.NewExactSolver(0.5).Solve(board, eng.White)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Exact || result.Score != -1.5 {
+		t.Errorf("expected an exact score of -1.5 with white first, got %v (exact=%v)", result.Score, result.Exact)
+	}
+}
+
+// TestExactSolverThreeByTwo tests that the first player never loses on a 3x2 board
+func TestExactSolverThreeByTwo(t *testing.T) {
+	result, err := ai.NewExactSolver(0).Solve(eng.NewRectBoard(3, 2), eng.Black)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Exact || result.Score < 0 {
+		t.Errorf("expected an exact score of at least 0, got %v (exact=%v)", result.Score, result.Exact)
+	}
+}
+
+// TestExactSolverThreeByThree tests the known value of the 3x3 board, black takes all 9 points
+func TestExactSolverThreeByThree(t *testing.T) {
+	board := eng.NewBoard(3)
+	result, err := ai.NewExactSolver(0).Solve(board, eng.Black)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Exact || result.Score != 9 {
+		t.Errorf("expected an exact score of 9, got %v (exact=%v)", result.Score, result.Exact)
+	}
+	if len(result.PV) == 0 || result.PV[0].Point != board.ToPoint(2, 2) {
+		t.Errorf("expected a principal variation starting at the center, got %v", result.PV)
+	}
+}
+
+// TestExactSolverBudget tests that a cut off search is not reported as exact
+func TestExactSolverBudget(t *testing.T) {
+	solver := ai.NewExactSolver(0)
+	solver.MaxNodes = 1000
+	result, err := solver.Solve(eng.NewBoard(5), eng.Black)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Exact {
+		t.Error("expected a budget of 1000 nodes to leave 5x5 unsolved")
+	}
+	if result.Lower > result.Upper || result.Score != result.Lower {
+		t.Errorf("expected the score to be the lower of bounds [%v, %v], got %v", result.Lower, result.Upper, result.Score)
+	}
+	if result.Nodes > solver.MaxNodes {
+		t.Errorf("expected at most %d nodes, got %d", solver.MaxNodes, result.Nodes)
+	}
+}
+
+// TestExactSolverTooLarge tests that boards beyond 5x5 are refused
+func TestExactSolverTooLarge(t *testing.T) {
+	if _, err := ai.NewExactSolver(0).Solve(eng.NewBoard(9), eng.Black); err == nil {
+		t.Error("expected an error for a 9x9 board")
+	}
+}
+
+// TestSolverBot tests that the solver bot plays a legal move
+func TestSolverBot(t *testing.T) {
+	board := eng.NewRectBoard(2, 2)
+	move := ai.NewSolverBot(0).SelectMove(board, eng.Black)
+	if move.Point < 0 || !board.IsLegal(move.Point, eng.Black) {
+		t.Errorf("expected a legal move on the empty 2x2 board, got %s", board.FormatGTP(move.Point))
+	}
+
+	// too large to solve, the fallback bot plays
+	large := eng.NewBoard(9)
+	if move := ai.NewSolverBot(0).SelectMove(large, eng.Black); move.Point < 0 || !large.IsLegal(move.Point, eng.Black) {
+		t.Errorf("expected a legal fallback move on 9x9, got %s", large.FormatGTP(move.Point))
+	}
+}
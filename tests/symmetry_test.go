@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/awesohame/gogo/internal/ai"
+	eng "github.com/awesohame/gogo/internal/engine"
+)
+
+// plays n random legal moves on a board
+func randomPosition(board *eng.Board, n int, seed int64) *eng.Board {
+	rng := rand.New(rand.NewSource(seed))
+	color := eng.Black
+	for i := 0; i < n; i++ {
+		legal := board.LegalMoves(color)
+		if len(legal) == 0 {
+			break
+		}
+		board, _ = board.ApplyMove(eng.Move{Point: legal[rng.Intn(len(legal))], Color: color})
+		if color == eng.Black {
+			color = eng.White
+		} else {
+			color = eng.Black
+		}
+	}
+	return board
+}
+
+// TestTransformRoundTrip tests that every transform is undone by its inverse
+func TestTransformRoundTrip(t *testing.T) {
+	for _, board := range []*eng.Board{
+		randomPosition(eng.NewBoard(9), 30, 1),
+		randomPosition(eng.NewRectBoard(7, 4), 12, 2),
+	} {
+		for _, s := range eng.AllSymmetries {
+			transformed := board.Transform(s)
+			if s.SwapsAxes() && transformed.Width() != board.Height() {
+				t.Errorf("symmetry %d should swap width and height", s)
+			}
+			back := transformed.Transform(s.Inverse())
+			if back.Hash() != board.Hash() {
+				t.Errorf("symmetry %d: inverse does not restore the position\n%s\n%s", s, board, back)
+			}
+
+			// points and moves map like the stones do
+			p := board.ToPoint(2, 1)
+			moved := board.TransformMove(eng.Move{Point: p, Color: eng.Black}, s)
+			if transformed.ColorAt(moved.Point) != board.ColorAt(p) {
+				t.Errorf("symmetry %d: point maps to a different stone", s)
+			}
+		}
+	}
+}
+
+// TestCanonicalHash tests that all rotations and reflections share one canonical hash
+func TestCanonicalHash(t *testing.T) {
+	board := randomPosition(eng.NewBoard(9), 25, 3)
+	canonical := board.CanonicalHash()
+	for _, s := range eng.AllSymmetries {
+		if got := board.Transform(s).CanonicalHash(); got != canonical {
+			t.Errorf("symmetry %d changes the canonical hash", s)
+		}
+	}
+
+	other := randomPosition(eng.NewBoard(9), 25, 4)
+	if other.CanonicalHash() == canonical {
+		t.Error("different positions should have different canonical hashes")
+	}
+}
+
+// TestBoardSymmetries tests which symmetries leave a position unchanged
+func TestBoardSymmetries(t *testing.T) {
+	board := eng.NewBoard(9)
+	if got := len(board.Symmetries()); got != 8 {
+		t.Errorf("empty square board should have 8 symmetries, got %d", got)
+	}
+	if got := len(eng.NewRectBoard(9, 7).Symmetries()); got != 4 {
+		t.Errorf("empty rectangular board should have 4 symmetries, got %d", got)
+	}
+
+	board, _ = board.ApplyMove(eng.Move{Point: board.ToPoint(3, 3), Color: eng.Black})
+	if got := board.Symmetries(); len(got) != 2 || got[1] != eng.FlipDiagonal {
+		t.Errorf("stone on the diagonal should keep only the transpose, got %v", got)
+	}
+}
+
+// TestLegalOrbitSuperko tests that orbit members repeating an earlier position are left out
+func TestLegalOrbitSuperko(t *testing.T) {
+	board := eng.NewBoard(3)
+	moves := []struct {
+		x, y  int
+		color eng.Color
+	}{
+		{3, 2, eng.Black}, {1, 2, eng.White},
+		{2, 3, eng.Black}, {3, 1, eng.White},
+		{2, 2, eng.Black}, {2, 1, eng.White},
+		{1, 1, eng.Black}, // takes (2,1) and (3,1)
+		{2, 1, eng.White}, // takes (1,1) back
+	}
+	for _, m := range moves {
+		next, err := board.ApplyMove(eng.Move{Point: board.ToPoint(m.x, m.y), Color: m.color})
+		if err != nil {
+			t.Fatalf("setup move (%d,%d) failed: %v", m.x, m.y, err)
+		}
+		board = next
+	}
+
+	// the stones are symmetric, but white at (3,1) repeats the position before black took it
+	p, mirror := board.ToPoint(1, 3), board.ToPoint(3, 1)
+	if got := len(board.Symmetries()); got != 2 {
+		t.Fatalf("expected the position to keep one reflection, got %d symmetries\n%s", got, board.String())
+	}
+	if got := board.CheckLegality(mirror, eng.White); got != eng.KoViolation {
+		t.Fatalf("expected (3,1) to break superko, got %v", got)
+	}
+	if got := board.LegalOrbit(p, eng.White); len(got) != 1 || got[0] != p {
+		t.Errorf("expected only (1,3) in the orbit, got %v", got)
+	}
+	if got := board.LegalOrbit(board.ToPoint(1, 1), eng.White); len(got) != 1 {
+		t.Errorf("expected (1,1) to lie on the axis, got %v", got)
+	}
+}
+
+// TestMCTSMergesSymmetricRootMoves tests that the empty board searches each distinct move once
+func TestMCTSMergesSymmetricRootMoves(t *testing.T) {
+	bot := ai.NewMCTSBot(300)
+	bot.Verbose = false
+	result := bot.Search(eng.NewBoard(5), eng.Black)
+
+	// 6 points of the 5x5 board are distinct up to symmetry
+	if got := len(result.Candidates); got != 6 {
+		t.Errorf("expected 6 distinct root moves, got %d", got)
+	}
+	seen := make(map[uint64]bool)
+	board := eng.NewBoard(5)
+	for _, c := range result.Candidates {
+		next, err := board.ApplyMove(c.Move)
+		if err != nil {
+			t.Fatalf("candidate %v is illegal: %v", c.Move, err)
+		}
+		if seen[next.CanonicalHash()] {
+			t.Errorf("candidate %v repeats a symmetric move", c.Move)
+		}
+		seen[next.CanonicalHash()] = true
+	}
+}
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/awesohame/gogo/internal/ai"
+	eng "github.com/awesohame/gogo/internal/engine"
+)
+
+// TestClassifyMove tests each tactical category on small positions
+func TestClassifyMove(t *testing.T) {
+	cases := []struct {
+		name    string
+		diagram string
+		x, y    int
+		color   eng.Color
+		want    ai.Tactic
+	}{
+		{"capture", `
+			. X . . .
+			X O . . .
+			. X . . .
+			. . . . .
+			. . . . .`, 3, 2, eng.Black, ai.TacticCapture},
+		{"self-atari", `
+			. X . . .
+			. X . . .
+			X X . . .
+			. . . . .
+			. . . . .`, 1, 2, eng.White, ai.TacticSelfAtari},
+		{"connect", `
+			. . . . .
+			X . X . .
+			. . . . .
+			. O . O .
+			. . . . .`, 2, 2, eng.Black, ai.TacticConnect},
+		{"cut", `
+			. . . . .
+			X . X . .
+			. . . . .
+			. O . O .
+			. . . . .`, 3, 4, eng.Black, ai.TacticCut},
+		{"quiet move", `
+			. . . . .
+			. . . . .
+			. . . . .
+			. . . . .
+			. . . . .`, 3, 3, eng.Black, ai.TacticNone},
+		{"ladder", `
+			. . . . . . . . .
+			. . . . . . . . .
+			. . X X . . . . .
+			. X O . . . . . .
+			. . . . . . . . .
+			. . . . . . . . .
+			. . . . . . . . .
+			. . . . . . . . .
+			. . . . . . . . .`, 3, 5, eng.Black, ai.TacticAtari | ai.TacticLadder},
+		{"atari from the wrong side", `
+			. . . . . . . . .
+			. . . . . . . . .
+			. . X X . . . . .
+			. X O . . . . . .
+			. . . . . . . . .
+			. . . . . . . . .
+			. . . . . . . . .
+			. . . . . . . . .
+			. . . . . . . . .`, 4, 4, eng.Black, ai.TacticAtari},
+		{"escape", fmt.Sprintf(ladderStart, "O", "."), 4, 4, eng.White, ai.TacticEscape},
+		{"lost ladder", fmt.Sprintf(ladderStart, ".", "."), 4, 4, eng.White, ai.TacticNone},
+	}
+
+	for _, tc := range cases {
+		board, err := eng.ParseDiagram(tc.diagram)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		move := eng.Move{Point: board.ToPoint(tc.x, tc.y), Color: tc.color}
+		if got := ai.ClassifyMove(board, move); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+// TestClassifyIllegalMove tests that occupied points and suicides have no category
+func TestClassifyIllegalMove(t *testing.T) {
+	board, err := eng.ParseDiagram(`
+		. X . . .
+		X . . . .
+		. . . . .
+		. . . . .
+		. . . . .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	suicide := eng.Move{Point: board.ToPoint(1, 1), Color: eng.White}
+	if got := ai.ClassifyMove(board, suicide); got != ai.TacticNone {
+		t.Errorf("expected none for suicide, got %v", got)
+	}
+	occupied := eng.Move{Point: board.ToPoint(2, 1), Color: eng.White}
+	if got := ai.ClassifyMove(board, occupied); got != ai.TacticNone {
+		t.Errorf("expected none for occupied point, got %v", got)
+	}
+}
+
+// TestTacticString tests the category names
+func TestTacticString(t *testing.T) {
+	if got := (ai.TacticCapture | ai.TacticEscape).String(); got != "capture|escape" {
+		t.Errorf("expected capture|escape, got %s", got)
+	}
+	if got := ai.TacticNone.String(); got != "none" {
+		t.Errorf("expected none, got %s", got)
+	}
+}
+
+// TestIsCriticalMove tests that tactics count as critical and self-ataris do not
+func TestIsCriticalMove(t *testing.T) {
+	board, err := eng.ParseDiagram(`
+		. X . . .
+		X O . . .
+		. X . . .
+		. . . . .
+		. . . . .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ai.IsCriticalMove(board, eng.Move{Point: board.ToPoint(3, 2), Color: eng.Black}) {
+		t.Error("expected capture to be critical")
+	}
+	if ai.IsCriticalMove(board, eng.Move{Point: board.ToPoint(4, 4), Color: eng.Black}) {
+		t.Error("expected quiet move not to be critical")
+	}
+	if !ai.IsCriticalMove(board, eng.Move{Point: board.ToPoint(3, 2), Color: eng.White}) {
+		t.Error("expected escape to be critical")
+	}
+
+	// walking into the corner only helps black
+	board, err = eng.ParseDiagram(`
+		. X . . .
+		. X . . .
+		X X . . .
+		. . . . .
+		. . . . .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ai.IsCriticalMove(board, eng.Move{Point: board.ToPoint(1, 2), Color: eng.White}) {
+		t.Error("expected self-atari not to be critical")
+	}
+}
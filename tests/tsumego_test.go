@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/awesohame/gogo/internal/ai"
+	eng "github.com/awesohame/gogo/internal/engine"
+)
+
+// white's straight three in the corner, the middle point decides
+const straightThree = `
+	. . . O X .
+	O O O O X .
+	X X X X X .
+	. . . . . .`
+
+// TestTsumegoStraightThree tests that whoever plays first at the vital point wins
+func TestTsumegoStraightThree(t *testing.T) {
+	board, err := eng.ParseDiagram(straightThree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := board.ToPoint(1, 2)
+	vital := board.ToPoint(2, 1)
+
+	cases := []struct {
+		toMove eng.Color
+		status ai.TsumegoStatus
+	}{
+		{eng.Black, ai.TsumegoDies},
+		{eng.White, ai.TsumegoLives},
+	}
+	for _, tc := range cases {
+		result, err := ai.NewTsumegoSolver().Solve(board, target, nil, tc.toMove)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Status != tc.status || !result.Success {
+			t.Errorf("%v to move: expected %v with success, got %v (success=%v)", tc.toMove, tc.status, result.Status, result.Success)
+		}
+		if result.KeyMove.Point != vital {
+			t.Errorf("%v to move: expected key move %s, got %s", tc.toMove, board.FormatGTP(vital), board.FormatGTP(result.KeyMove.Point))
+		}
+		if len(result.Tree) != 1 || result.Tree[0].Move != result.KeyMove {
+			t.Errorf("%v to move: expected the tree to start with the key move", tc.toMove)
+		}
+	}
+}
+
+// TestTsumegoRefutations tests that a failed attack lists every try with its refutation
+func TestTsumegoRefutations(t *testing.T) {
+	// a straight four in the corner lives whatever black does
+	board, err := eng.ParseDiagram(`
+		. . . . O X .
+		O O O O O X .
+		X X X X X X .
+		. . . . . . .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	region := []eng.Point{board.ToPoint(1, 1), board.ToPoint(2, 1), board.ToPoint(3, 1), board.ToPoint(4, 1)}
+
+	result, err := ai.NewTsumegoSolver().Solve(board, board.ToPoint(1, 2), region, eng.Black)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != ai.TsumegoLives || result.Success {
+		t.Fatalf("expected the group to live against black, got %v (success=%v)", result.Status, result.Success)
+	}
+	if result.KeyMove.Point != -1 {
+		t.Errorf("expected no key move, got %s", board.FormatGTP(result.KeyMove.Point))
+	}
+	if len(result.Tree) != len(region) {
+		t.Fatalf("expected %d tries, got %d", len(region), len(result.Tree))
+	}
+	for _, try := range result.Tree {
+		if try.Move.Color != eng.Black || len(try.Replies) != 1 || try.Replies[0].Move.Color != eng.White {
+			t.Errorf("expected black try %s to have one white refutation", board.FormatGTP(try.Move.Point))
+		}
+	}
+}
+
+// TestTsumegoRegion tests that a region restricted to the eye space gives the same answer
+func TestTsumegoRegion(t *testing.T) {
+	board, err := eng.ParseDiagram(straightThree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	region := []eng.Point{board.ToPoint(1, 1), board.ToPoint(2, 1), board.ToPoint(3, 1)}
+
+	result, err := ai.NewTsumegoSolver().Solve(board, board.ToPoint(4, 1), region, eng.Black)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != ai.TsumegoDies || result.KeyMove.Point != board.ToPoint(2, 1) {
+		t.Errorf("expected black to kill at B4, got %v at %s", result.Status, board.FormatGTP(result.KeyMove.Point))
+	}
+}
+
+// TestTsumegoBudget tests that running out of nodes gives an unknown status
+func TestTsumegoBudget(t *testing.T) {
+	board, err := eng.ParseDiagram(straightThree)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	solver := ai.NewTsumegoSolver()
+	solver.MaxNodes = 5
+	result, err := solver.Solve(board, board.ToPoint(1, 2), nil, eng.Black)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != ai.TsumegoUnknown || result.Nodes > 5 {
+		t.Errorf("expected unknown within 5 nodes, got %v after %d", result.Status, result.Nodes)
+	}
+}
+
+// TestTsumegoEmptyTarget tests that the target must be a stone
+func TestTsumegoEmptyTarget(t *testing.T) {
+	board, err := eng.ParseDiagram(straightThree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ai.NewTsumegoSolver().Solve(board, board.ToPoint(1, 1), nil, eng.Black); err == nil {
+		t.Error("expected an error for an empty target")
+	}
+}